@@ -0,0 +1,172 @@
+package assert
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"testing"
+)
+
+// fakeTB records the failures reported to it instead of failing the test.
+type fakeTB struct {
+	testing.TB
+	errors, fatals []string
+	helpers        int
+}
+
+func (tb *fakeTB) Helper()           { tb.helpers++ }
+func (tb *fakeTB) Error(args ...any) { tb.errors = append(tb.errors, fmt.Sprint(args...)) }
+func (tb *fakeTB) Fatal(args ...any) { tb.fatals = append(tb.fatals, fmt.Sprint(args...)) }
+
+func (tb *fakeTB) Errorf(format string, args ...any) {
+	tb.errors = append(tb.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertions(t *testing.T) {
+	var nilMap map[string]int
+	pathErr := fmt.Errorf("open: %w", &fs.PathError{Op: "open", Path: "x", Err: fs.ErrNotExist})
+	cases := []struct {
+		name    string
+		pass    func(tb testing.TB)
+		fail    func(tb testing.TB)
+		failure string
+	}{
+		{
+			"Nil",
+			func(tb testing.TB) { NilErrorf(tb, nilMap, "m"); NilFatalf(tb, nil, "nil") },
+			func(tb testing.TB) { NilErrorf(tb, 1, "m"); NilFatalf(tb, 1, "m") },
+			"expect:nil\nactual:1\nmsg:m",
+		},
+		{
+			"NotNil",
+			func(tb testing.TB) { NotNilErrorf(tb, 0, "m"); NotNilFatalf(tb, &struct{}{}, "m") },
+			func(tb testing.TB) { NotNilErrorf(tb, nilMap, "m"); NotNilFatalf(tb, nilMap, "m") },
+			"expect:not nil\nactual:map[string]int(nil)\nmsg:m",
+		},
+		{
+			"Zero",
+			func(tb testing.TB) { ZeroErrorf(tb, "", "m"); ZeroFatalf(tb, struct{ A int }{}, "m") },
+			func(tb testing.TB) { ZeroErrorf(tb, "a", "m"); ZeroFatalf(tb, "a", "m") },
+			"expect:zero value\nactual:\"a\"\nmsg:m",
+		},
+		{
+			"InDelta",
+			func(tb testing.TB) { InDeltaErrorf(tb, 0.3, 0.1+0.2, 1e-9, "m"); InDeltaFatalf(tb, 1, 1.5, 0.5, "m") },
+			func(tb testing.TB) { InDeltaErrorf(tb, 1, 2, 0.5, "m"); InDeltaFatalf(tb, 1, 2, 0.5, "m") },
+			"expect:1 ± 0.5\nactual:2 (off by 1)\nmsg:m",
+		},
+		{
+			"NoError",
+			func(tb testing.TB) { NoErrorErrorf(tb, nil, "m"); NoErrorFatalf(tb, nil, "m") },
+			func(tb testing.TB) { NoErrorErrorf(tb, os.ErrClosed, "m"); NoErrorFatalf(tb, os.ErrClosed, "m") },
+			"expect:no error\nactual:file already closed\nmsg:m",
+		},
+		{
+			"ErrorIs",
+			func(tb testing.TB) {
+				ErrorIsErrorf(tb, pathErr, fs.ErrNotExist, "m")
+				ErrorIsFatalf(tb, pathErr, fs.ErrNotExist, "m")
+			},
+			func(tb testing.TB) {
+				ErrorIsErrorf(tb, pathErr, fs.ErrExist, "m")
+				ErrorIsFatalf(tb, pathErr, fs.ErrExist, "m")
+			},
+			"expect:error matching \"file already exists\"\nactual:open: open x: file does not exist\nmsg:m",
+		},
+		{
+			"ErrorAs",
+			func(tb testing.TB) {
+				ErrorAsErrorf[*fs.PathError](tb, pathErr, "m")
+				if ErrorAsFatalf[*fs.PathError](tb, pathErr, "m").Path != "x" {
+					tb.Fatal("wrong path")
+				}
+			},
+			func(tb testing.TB) {
+				ErrorAsErrorf[*os.SyscallError](tb, pathErr, "m")
+				ErrorAsFatalf[*os.SyscallError](tb, pathErr, "m")
+			},
+			"expect:error as *os.SyscallError\nactual:open: open x: file does not exist\nmsg:m",
+		},
+		{
+			"Panics",
+			func(tb testing.TB) {
+				if PanicsErrorf(tb, func() { panic("boom") }, "m") != "boom" {
+					tb.Fatal("wrong value")
+				}
+				PanicsFatalf(tb, func() { panic(1) }, "m")
+			},
+			func(tb testing.TB) { PanicsErrorf(tb, func() {}, "m"); PanicsFatalf(tb, func() {}, "m") },
+			"expect:panic\nactual:returned\nmsg:m",
+		},
+		{
+			"PanicsWith",
+			func(tb testing.TB) {
+				PanicsWithErrorf(tb, []int{1}, func() { panic([]int{1}) }, "m")
+				PanicsWithFatalf(tb, errors.New("x"), func() { panic(errors.New("x")) }, "m")
+			},
+			func(tb testing.TB) {
+				PanicsWithErrorf(tb, "a", func() { panic("b") }, "m")
+				PanicsWithFatalf(tb, "a", func() { panic("b") }, "m")
+			},
+			"panic value diff (expect != actual):\n\"a\" != \"b\"\nmsg:m",
+		},
+		{
+			"Contains",
+			func(tb testing.TB) {
+				ContainsErrorf(tb, "hello", "ell", "m")
+				ContainsErrorf(tb, []item{{Name: "a"}, {Name: "b"}}, item{Name: "b"}, "m")
+				ContainsFatalf(tb, map[int]bool{1: false}, 1, "m")
+			},
+			func(tb testing.TB) {
+				ContainsErrorf(tb, []int{1, 2}, 3, "m")
+				ContainsFatalf(tb, []int{1, 2}, 3, "m")
+			},
+			"expect:[]int{1, 2} to contain 3\nmsg:m",
+		},
+		{
+			"Len",
+			func(tb testing.TB) { LenErrorf(tb, "abc", 3, "m"); LenFatalf(tb, map[int]int{1: 1}, 1, "m") },
+			func(tb testing.TB) { LenErrorf(tb, []int{1}, 2, "m"); LenFatalf(tb, []int{1}, 2, "m") },
+			"expect:len 2\nactual:len 1 of []int{1}\nmsg:m",
+		},
+		{
+			"ElementsMatch",
+			func(tb testing.TB) {
+				ElementsMatchErrorf(tb, []string{"a", "b", "a"}, []string{"a", "a", "b"}, "m")
+				ElementsMatchFatalf(tb, nil, []int{}, "m")
+			},
+			func(tb testing.TB) {
+				ElementsMatchErrorf(tb, []int{1, 2}, []int{2, 3}, "m")
+				ElementsMatchFatalf(tb, []int{1, 2}, []int{2, 3}, "m")
+			},
+			"unmatched elements (expect != actual):\n[0]: 1 != <missing>\n[1]: <missing> != 3\nmsg:m",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			tb := &fakeTB{TB: t}
+			c.pass(tb)
+			EqualErrorf(t, 0, len(tb.errors)+len(tb.fatals), "passing: %q %q", tb.errors, tb.fatals)
+
+			tb = &fakeTB{TB: t}
+			c.fail(tb)
+			EqualFatalf(t, 1, len(tb.errors), "errors: %q", tb.errors)
+			EqualFatalf(t, 1, len(tb.fatals), "fatals: %q", tb.fatals)
+			EqualErrorf(t, "\n"+c.failure+"\n", tb.errors[0], "error")
+			EqualErrorf(t, tb.errors[0], tb.fatals[0], "fatal")
+			EqualErrorf(t, true, tb.helpers >= 4, "helpers: %d", tb.helpers)
+		})
+	}
+}
+
+func TestEqualFailure(t *testing.T) {
+	tb := &fakeTB{TB: t}
+	EqualErrorf(tb, 1, 2, "count %d", 3)
+	DeepEqualFatalf(tb, []int{1}, []int{2}, "slice")
+	With().DeepEqualErrorf(tb, []int{1}, []int{1}, "equal")
+	EqualErrorf(t, "\nexpect:1\nactual:2\nmsg:count 3\n", strings.Join(tb.errors, ""), "error")
+	EqualErrorf(t, "\ndiff (expect != actual):\n[0]: 1 != 2\nmsg:slice\n", strings.Join(tb.fatals, ""), "fatal")
+}
@@ -0,0 +1,110 @@
+package assert
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func checkContains(container, elem any) string {
+	c := reflect.ValueOf(container)
+	failure := fmt.Sprintf("expect:%s to contain %s", formatValue(c), formatValue(reflect.ValueOf(elem)))
+	switch c.Kind() {
+	case reflect.String:
+		s, ok := elem.(string)
+		if !ok {
+			return fmt.Sprintf("expect:string element\nactual:%T", elem)
+		}
+		if strings.Contains(c.String(), s) {
+			return ""
+		}
+		return failure
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < c.Len(); i++ {
+			if Diff(c.Index(i).Interface(), elem) == "" {
+				return ""
+			}
+		}
+		return failure
+	case reflect.Map:
+		k := reflect.ValueOf(elem)
+		if !k.IsValid() || !k.Type().AssignableTo(c.Type().Key()) {
+			return fmt.Sprintf("expect:%v key\nactual:%T", c.Type().Key(), elem)
+		}
+		if c.MapIndex(k).IsValid() {
+			return ""
+		}
+		return failure
+	}
+	return fmt.Sprintf("expect:string, slice, array or map\nactual:%T", container)
+}
+
+func checkLen(v any, n int) string {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map, reflect.Chan:
+		if rv.Len() == n {
+			return ""
+		}
+		return fmt.Sprintf("expect:len %d\nactual:len %d of %s", n, rv.Len(), formatValue(rv))
+	}
+	return fmt.Sprintf("expect:string, slice, array, map or channel\nactual:%T", v)
+}
+
+func checkElementsMatch(expect, actual any) string {
+	d := differ{visited: make(map[visit]bool)}
+	d.diffUnordered("", addressable(expect), addressable(actual))
+	if diff := d.String(); diff != "" {
+		return "unmatched elements (expect != actual):\n" + diff
+	}
+	return ""
+}
+
+// ContainsErrorf checks that container holds elem: a substring of a string,
+// an element of a slice or array deeply equal to elem, or a key of a map.
+func ContainsErrorf(t testing.TB, container, elem any, format string, args ...any) {
+	t.Helper()
+	if failure := checkContains(container, elem); failure != "" {
+		fail(t, false, failure, format, args...)
+	}
+}
+
+func ContainsFatalf(t testing.TB, container, elem any, format string, args ...any) {
+	t.Helper()
+	if failure := checkContains(container, elem); failure != "" {
+		fail(t, true, failure, format, args...)
+	}
+}
+
+// LenErrorf checks that v is a string, slice, array, map or channel of
+// length n.
+func LenErrorf(t testing.TB, v any, n int, format string, args ...any) {
+	t.Helper()
+	if failure := checkLen(v, n); failure != "" {
+		fail(t, false, failure, format, args...)
+	}
+}
+
+func LenFatalf(t testing.TB, v any, n int, format string, args ...any) {
+	t.Helper()
+	if failure := checkLen(v, n); failure != "" {
+		fail(t, true, failure, format, args...)
+	}
+}
+
+// ElementsMatchErrorf checks that expect and actual hold deeply equal
+// elements, in any order.
+func ElementsMatchErrorf[T any](t testing.TB, expect, actual []T, format string, args ...any) {
+	t.Helper()
+	if failure := checkElementsMatch(expect, actual); failure != "" {
+		fail(t, false, failure, format, args...)
+	}
+}
+
+func ElementsMatchFatalf[T any](t testing.TB, expect, actual []T, format string, args ...any) {
+	t.Helper()
+	if failure := checkElementsMatch(expect, actual); failure != "" {
+		fail(t, true, failure, format, args...)
+	}
+}
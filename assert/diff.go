@@ -0,0 +1,394 @@
+package assert
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxDiffs bounds the differences reported by Diff.
+const maxDiffs = 50
+
+// Diff returns the differences between expect and actual, one per line as
+// "path: expect != actual", or "" if they are deeply equal. Unexported
+// fields are compared too, and cycles are followed only once. Long or
+// multi-line strings are shown as a unified diff.
+func Diff(expect, actual any) string {
+	return With().Diff(expect, actual)
+}
+
+type visit struct {
+	a, b uintptr
+	typ  reflect.Type
+}
+
+type differ struct {
+	o       *options
+	visited map[visit]bool
+	diffs   []string
+	more    int
+}
+
+func (d *differ) String() string {
+	s := strings.Join(d.diffs, "\n")
+	if d.more > 0 {
+		s += fmt.Sprintf("\n... and %d more differences", d.more)
+	}
+	return s
+}
+
+func (d *differ) report(path, format string, args ...any) {
+	if len(d.diffs) == maxDiffs {
+		d.more++
+		return
+	}
+	line := fmt.Sprintf(format, args...)
+	if path != "" {
+		line = path + ": " + line
+	}
+	d.diffs = append(d.diffs, line)
+}
+
+func (d *differ) reportValues(path string, a, b reflect.Value) {
+	d.report(path, "%s != %s", formatValue(a), formatValue(b))
+}
+
+// seen reports whether the pair of references was compared already, which
+// happens with cycles.
+func (d *differ) seen(a, b reflect.Value) bool {
+	v := visit{a.Pointer(), b.Pointer(), a.Type()}
+	if d.visited[v] {
+		return true
+	}
+	d.visited[v] = true
+	return false
+}
+
+func (d *differ) diff(path string, a, b reflect.Value) {
+	if !a.IsValid() || !b.IsValid() {
+		if a.IsValid() != b.IsValid() {
+			d.reportValues(path, a, b)
+		}
+		return
+	}
+	if a.Type() != b.Type() {
+		d.report(path, "type %v != %v", a.Type(), b.Type())
+		return
+	}
+	if equal, ok := d.o.custom(a, b); ok {
+		if !equal {
+			d.reportValues(path, a, b)
+		}
+		return
+	}
+
+	switch a.Kind() {
+	case reflect.Bool:
+		if a.Bool() != b.Bool() {
+			d.reportValues(path, a, b)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if a.Int() != b.Int() {
+			d.reportValues(path, a, b)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if a.Uint() != b.Uint() {
+			d.reportValues(path, a, b)
+		}
+	case reflect.Float32, reflect.Float64:
+		if !d.o.floatEqual(a.Float(), b.Float()) {
+			d.reportValues(path, a, b)
+		}
+	case reflect.Complex64, reflect.Complex128:
+		if a.Complex() != b.Complex() {
+			d.reportValues(path, a, b)
+		}
+	case reflect.String:
+		d.diffString(path, a, b)
+	case reflect.Chan, reflect.UnsafePointer:
+		if a.Pointer() != b.Pointer() {
+			d.reportValues(path, a, b)
+		}
+	case reflect.Func:
+		if !a.IsNil() || !b.IsNil() {
+			d.report(path, "func values are only equal when nil")
+		}
+	case reflect.Pointer:
+		if a.Pointer() == b.Pointer() {
+			return
+		}
+		if a.IsNil() || b.IsNil() {
+			d.reportValues(path, a, b)
+			return
+		}
+		if !d.seen(a, b) {
+			d.diff(path, a.Elem(), b.Elem())
+		}
+	case reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() != b.IsNil() {
+				d.reportValues(path, a, b)
+			}
+			return
+		}
+		d.diff(path, a.Elem(), b.Elem())
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			if d.o.ignoreField(a.Type(), a.Type().Field(i).Name) {
+				continue
+			}
+			d.diff(path+"."+a.Type().Field(i).Name, a.Field(i), b.Field(i))
+		}
+	case reflect.Array:
+		d.diffElems(path, a, b)
+	case reflect.Slice:
+		if a.IsNil() != b.IsNil() {
+			d.reportValues(path, a, b)
+			return
+		}
+		if a.Pointer() == b.Pointer() && a.Len() == b.Len() || d.seen(a, b) {
+			return
+		}
+		d.diffElems(path, a, b)
+	case reflect.Map:
+		if a.IsNil() != b.IsNil() {
+			d.reportValues(path, a, b)
+			return
+		}
+		if a.Pointer() == b.Pointer() || d.seen(a, b) {
+			return
+		}
+		d.diffMap(path, a, b)
+	}
+}
+
+func (d *differ) diffElems(path string, a, b reflect.Value) {
+	if d.o != nil && d.o.unordered {
+		d.diffUnordered(path, a, b)
+		return
+	}
+	for i := 0; i < max(a.Len(), b.Len()); i++ {
+		p := path + "[" + strconv.Itoa(i) + "]"
+		switch {
+		case i >= a.Len():
+			d.report(p, "<missing> != %s", formatValue(b.Index(i)))
+		case i >= b.Len():
+			d.report(p, "%s != <missing>", formatValue(a.Index(i)))
+		default:
+			d.diff(p, a.Index(i), b.Index(i))
+		}
+	}
+}
+
+func (d *differ) diffMap(path string, a, b reflect.Value) {
+	keys := a.MapKeys()
+	for _, k := range b.MapKeys() {
+		if !a.MapIndex(k).IsValid() {
+			keys = append(keys, k)
+		}
+	}
+	sortValues(keys)
+	for _, k := range keys {
+		if d.o.ignoreKey(k) {
+			continue
+		}
+		p := path + "[" + formatValue(k) + "]"
+		av, bv := a.MapIndex(k), b.MapIndex(k)
+		switch {
+		case !av.IsValid():
+			d.report(p, "<missing> != %s", formatValue(bv))
+		case !bv.IsValid():
+			d.report(p, "%s != <missing>", formatValue(av))
+		default:
+			d.diff(p, av, bv)
+		}
+	}
+}
+
+// diffUnordered matches the elements of a and b as multisets, reporting
+// the ones left unmatched.
+func (d *differ) diffUnordered(path string, a, b reflect.Value) {
+	matched := make([]bool, b.Len())
+	for i := 0; i < a.Len(); i++ {
+		found := false
+		for j := 0; j < b.Len() && !found; j++ {
+			if !matched[j] && d.equal(a.Index(i), b.Index(j)) {
+				matched[j], found = true, true
+			}
+		}
+		if !found {
+			d.report(path+"["+strconv.Itoa(i)+"]", "%s != <missing>", formatValue(a.Index(i)))
+		}
+	}
+	for j, ok := range matched {
+		if !ok {
+			d.report(path+"["+strconv.Itoa(j)+"]", "<missing> != %s", formatValue(b.Index(j)))
+		}
+	}
+}
+
+func (d *differ) equal(a, b reflect.Value) bool {
+	sub := differ{o: d.o, visited: make(map[visit]bool)}
+	sub.diff("", a, b)
+	return len(sub.diffs) == 0
+}
+
+// longString is the length from which differing strings are shown as a
+// unified diff.
+const longString = 64
+
+func (d *differ) diffString(path string, a, b reflect.Value) {
+	as, bs := a.String(), b.String()
+	if as == bs {
+		return
+	}
+	if len(as) < longString && len(bs) < longString && !strings.Contains(as+bs, "\n") {
+		d.reportValues(path, a, b)
+		return
+	}
+	d.report(path, "strings differ (-expect +actual):\n%s", unifiedDiff(as, bs))
+}
+
+// sortValues sorts map keys by their formatted value, so that diffs are
+// reported in a stable order.
+func sortValues(values []reflect.Value) {
+	formatted := make(map[reflect.Value]string, len(values))
+	for _, v := range values {
+		formatted[v] = formatValue(v)
+	}
+	sort.SliceStable(values, func(i, j int) bool {
+		vi, vj := values[i], values[j]
+		switch vi.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return vi.Int() < vj.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return vi.Uint() < vj.Uint()
+		case reflect.Float32, reflect.Float64:
+			return vi.Float() < vj.Float()
+		}
+		return formatted[vi] < formatted[vj]
+	})
+}
+
+// formatValue formats v like %#v, basic kinds without needing Interface,
+// which the unexported fields of non-addressable values don't allow.
+func formatValue(v reflect.Value) string {
+	if !v.IsValid() {
+		return "nil"
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits())
+	case reflect.Complex64, reflect.Complex128:
+		return strconv.FormatComplex(v.Complex(), 'g', -1, v.Type().Bits())
+	case reflect.String:
+		return strconv.Quote(v.String())
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan, reflect.Interface:
+		switch {
+		case !v.IsNil():
+		case v.Kind() == reflect.Interface:
+			return "nil"
+		case v.Kind() == reflect.Map || v.Kind() == reflect.Slice:
+			return fmt.Sprintf("%v(nil)", v.Type())
+		default:
+			return fmt.Sprintf("(%v)(nil)", v.Type())
+		}
+		if v.Kind() == reflect.Interface {
+			return formatValue(v.Elem())
+		}
+	}
+	if i, ok := interfaceOf(v); ok && v.Kind() != reflect.Map {
+		if s := fmt.Sprintf("%#v", i); len(s) <= 2*longString {
+			return s
+		}
+	}
+	if v.Kind() == reflect.Map || v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		return fmt.Sprintf("%v{len %d}", v.Type(), v.Len())
+	}
+	return fmt.Sprintf("%v{...}", v.Type())
+}
+
+// unifiedDiff returns the line diff of a and b with 3 lines of context.
+func unifiedDiff(a, b string) string {
+	as, bs := strings.Split(a, "\n"), strings.Split(b, "\n")
+	// lcs[i][j] is the length of the longest common subsequence of as[i:]
+	// and bs[j:].
+	lcs := make([][]int, len(as)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(bs)+1)
+	}
+	for i := len(as) - 1; i >= 0; i-- {
+		for j := len(bs) - 1; j >= 0; j-- {
+			if as[i] == bs[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	type line struct {
+		op   byte // ' ', '-' or '+'
+		text string
+		a, b int // line numbers in a and b, from 1
+	}
+	var lines []line
+	i, j := 0, 0
+	for i < len(as) || j < len(bs) {
+		switch {
+		case i < len(as) && j < len(bs) && as[i] == bs[j]:
+			lines = append(lines, line{' ', as[i], i + 1, j + 1})
+			i++
+			j++
+		case j < len(bs) && (i == len(as) || lcs[i][j+1] > lcs[i+1][j]):
+			lines = append(lines, line{'+', bs[j], i + 1, j + 1})
+			j++
+		default:
+			lines = append(lines, line{'-', as[i], i + 1, j + 1})
+			i++
+		}
+	}
+
+	const context = 3
+	var out strings.Builder
+	for start := 0; start < len(lines); {
+		if lines[start].op == ' ' {
+			start++
+			continue
+		}
+		// a hunk spans the changes less than 2*context lines apart
+		first := max(start-context, 0)
+		end := start
+		for k := start; k < len(lines) && k-end <= 2*context; k++ {
+			if lines[k].op != ' ' {
+				end = k
+			}
+		}
+		last := min(end+context, len(lines)-1)
+		na, nb := 0, 0
+		for _, l := range lines[first : last+1] {
+			if l.op != '+' {
+				na++
+			}
+			if l.op != '-' {
+				nb++
+			}
+		}
+		fmt.Fprintf(&out, "@@ -%d,%d +%d,%d @@\n", lines[first].a, na, lines[first].b, nb)
+		for _, l := range lines[first : last+1] {
+			out.WriteByte(l.op)
+			out.WriteString(l.text)
+			out.WriteByte('\n')
+		}
+		start = last + 1
+	}
+	return strings.TrimSuffix(out.String(), "\n")
+}
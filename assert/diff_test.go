@@ -0,0 +1,87 @@
+package assert
+
+import (
+	"strings"
+	"testing"
+)
+
+type item struct {
+	Name  string
+	Tags  []string
+	price int
+}
+
+type order struct {
+	ID    int
+	Items []item
+	Meta  map[string]any
+	Next  *order
+}
+
+func TestDiff(t *testing.T) {
+	expect := order{
+		ID:    1,
+		Items: []item{{Name: "a", price: 1}, {Name: "b", Tags: []string{"x"}}},
+		Meta:  map[string]any{"k": 1, "gone": true},
+	}
+	actual := order{
+		ID:    1,
+		Items: []item{{Name: "a", price: 2}, {Name: "c", Tags: []string{"x", "y"}}, {}},
+		Meta:  map[string]any{"k": "1", "new": nil},
+	}
+	EqualErrorf(t, "", Diff(expect, expect), "equal")
+	EqualErrorf(t, strings.Join([]string{
+		`.Items[0].price: 1 != 2`,
+		`.Items[1].Name: "b" != "c"`,
+		`.Items[1].Tags[1]: <missing> != "y"`,
+		`.Items[2]: <missing> != assert.item{Name:"", Tags:[]string(nil), price:0}`,
+		`.Meta["gone"]: true != <missing>`,
+		`.Meta["k"]: type int != string`,
+		`.Meta["new"]: <missing> != nil`,
+	}, "\n"), Diff(expect, actual), "diff")
+
+	EqualErrorf(t, "1 != 2", Diff(1, 2), "root")
+	EqualErrorf(t, "type int != string", Diff(1, "1"), "root types")
+	EqualErrorf(t, "[]int(nil) != []int{}", Diff([]int(nil), []int{}), "nil slice")
+	EqualErrorf(t, "[2]: 3 != 4", Diff(&[]int{1, 2, 3}, &[]int{1, 2, 4}), "through pointers")
+}
+
+func TestDiffCycle(t *testing.T) {
+	a := &order{ID: 1}
+	a.Next = &order{ID: 2, Next: a}
+	b := &order{ID: 1}
+	b.Next = &order{ID: 2, Next: b}
+	EqualErrorf(t, "", Diff(a, b), "equal cycles")
+
+	b.Next.ID = 3
+	EqualErrorf(t, ".Next.ID: 2 != 3", Diff(a, b), "differing cycles")
+}
+
+func TestDiffLongString(t *testing.T) {
+	expect := "line 1\nline 2\nline 3\nline 4\nline 5\nline 6\nline 7\nline 8\nline 9"
+	actual := "line 1\nline 2\nline 3\nline 4\nline five\nline 6\nline 7\nline 8\nline 9\nline 10"
+	EqualErrorf(t, strings.Join([]string{
+		".Name: strings differ (-expect +actual):",
+		"@@ -2,8 +2,9 @@",
+		" line 2",
+		" line 3",
+		" line 4",
+		"-line 5",
+		"+line five",
+		" line 6",
+		" line 7",
+		" line 8",
+		" line 9",
+		"+line 10",
+	}, "\n"), Diff(item{Name: expect}, item{Name: actual}), "unified diff")
+}
+
+func TestDiffLimit(t *testing.T) {
+	a, b := make([]int, maxDiffs+5), make([]int, maxDiffs+5)
+	for i := range b {
+		b[i] = 1
+	}
+	lines := strings.Split(Diff(a, b), "\n")
+	EqualFatalf(t, maxDiffs+1, len(lines), "lines")
+	EqualErrorf(t, "... and 5 more differences", lines[maxDiffs], "last line")
+}
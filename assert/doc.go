@@ -0,0 +1,19 @@
+// Package assert provides test assertions reporting the expected and actual
+// values, in pairs of functions: XErrorf marks the test failed and goes on,
+// XFatalf stops it.
+//
+// EqualErrorf compares with ==, DeepEqualErrorf deeply. Options relaxing deep
+// comparisons, like IgnoreFields or FloatTolerance, don't fit in the
+// signatures of these helpers, whose trailing arguments format the message,
+// so they are bound to a Comparer instead, which has the same helpers:
+//
+//	cmp := assert.With(assert.IgnoreFields[User]("UpdatedAt"))
+//	cmp.DeepEqualErrorf(t, expect, actual, "user")
+//
+// Golden and snapshot assertions compare against files under testdata, which
+// go test -assert.update rewrites. The flag is namespaced so that it doesn't
+// clash with the -update flag test packages often declare themselves; when a
+// test package does declare a boolean -update, it rewrites the files too.
+// Files left behind by tests that no longer check them are reported by
+// GoldenMain.
+package assert
@@ -5,16 +5,45 @@ import (
 	"testing"
 )
 
-func EqualErrorf[T comparable](t *testing.T, expect, actual T, format string, args ...any) {
+// fail reports failure, followed by the message of the assertion, as an
+// error or, when fatal, as a fatal error.
+func fail(t testing.TB, fatal bool, failure, format string, args ...any) {
+	t.Helper()
+	msg := fmt.Sprintf("\n%s\nmsg:%s\n", failure, fmt.Sprintf(format, args...))
+	if fatal {
+		t.Fatal(msg)
+	} else {
+		t.Error(msg)
+	}
+}
+
+func EqualErrorf[T comparable](t testing.TB, expect, actual T, format string, args ...any) {
+	t.Helper()
 	if expect != actual {
-		t.Errorf("\nexpect:%v\nactual:%v\nmsg:%s\n", expect, actual,
-			fmt.Sprintf(format, args...))
+		fail(t, false, fmt.Sprintf("expect:%v\nactual:%v", expect, actual), format, args...)
 	}
 }
 
-func EqualFatalf[T comparable](t *testing.T, expect, actual T, format string, args ...any) {
+func EqualFatalf[T comparable](t testing.TB, expect, actual T, format string, args ...any) {
+	t.Helper()
 	if expect != actual {
-		t.Fatalf("\nexpect:%v\nactual:%v\nmsg:%s\n", expect, actual,
-			fmt.Sprintf(format, args...))
+		fail(t, true, fmt.Sprintf("expect:%v\nactual:%v", expect, actual), format, args...)
+	}
+}
+
+// DeepEqualErrorf is EqualErrorf for values of any type, compared deeply.
+// The failure shows where they differ, see Diff. Use With for a comparison
+// relaxed by Options.
+func DeepEqualErrorf[T any](t testing.TB, expect, actual T, format string, args ...any) {
+	t.Helper()
+	if diff := Diff(expect, actual); diff != "" {
+		fail(t, false, "diff (expect != actual):\n"+diff, format, args...)
+	}
+}
+
+func DeepEqualFatalf[T any](t testing.TB, expect, actual T, format string, args ...any) {
+	t.Helper()
+	if diff := Diff(expect, actual); diff != "" {
+		fail(t, true, "diff (expect != actual):\n"+diff, format, args...)
 	}
 }
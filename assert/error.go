@@ -0,0 +1,81 @@
+package assert
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func checkNoError(err error) string {
+	if err == nil {
+		return ""
+	}
+	return fmt.Sprintf("expect:no error\nactual:%v", err)
+}
+
+func checkErrorIs(err, target error) string {
+	if errors.Is(err, target) {
+		return ""
+	}
+	return fmt.Sprintf("expect:error matching %q\nactual:%v", target, err)
+}
+
+func checkErrorAs[E error](err error) (E, string) {
+	var target E
+	if errors.As(err, &target) {
+		return target, ""
+	}
+	return target, fmt.Sprintf("expect:error as %v\nactual:%v", reflect.TypeFor[E](), err)
+}
+
+func NoErrorErrorf(t testing.TB, err error, format string, args ...any) {
+	t.Helper()
+	if failure := checkNoError(err); failure != "" {
+		fail(t, false, failure, format, args...)
+	}
+}
+
+func NoErrorFatalf(t testing.TB, err error, format string, args ...any) {
+	t.Helper()
+	if failure := checkNoError(err); failure != "" {
+		fail(t, true, failure, format, args...)
+	}
+}
+
+// ErrorIsErrorf checks that errors.Is(err, target).
+func ErrorIsErrorf(t testing.TB, err, target error, format string, args ...any) {
+	t.Helper()
+	if failure := checkErrorIs(err, target); failure != "" {
+		fail(t, false, failure, format, args...)
+	}
+}
+
+func ErrorIsFatalf(t testing.TB, err, target error, format string, args ...any) {
+	t.Helper()
+	if failure := checkErrorIs(err, target); failure != "" {
+		fail(t, true, failure, format, args...)
+	}
+}
+
+// ErrorAsErrorf checks that err has an error of type E in its tree, and
+// returns it:
+//
+//	terr := assert.ErrorAsErrorf[*fsm.TransitionError](t, err, "fire")
+func ErrorAsErrorf[E error](t testing.TB, err error, format string, args ...any) E {
+	t.Helper()
+	target, failure := checkErrorAs[E](err)
+	if failure != "" {
+		fail(t, false, failure, format, args...)
+	}
+	return target
+}
+
+func ErrorAsFatalf[E error](t testing.TB, err error, format string, args ...any) E {
+	t.Helper()
+	target, failure := checkErrorAs[E](err)
+	if failure != "" {
+		fail(t, true, failure, format, args...)
+	}
+	return target
+}
@@ -0,0 +1,237 @@
+package assert
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Clock is what the waiting assertions need of a clock. clock.Real and
+// *clock.Fake implement it; the clock package isn't imported as its
+// dependencies are tested with assert.
+type Clock interface {
+	Now() time.Time
+	Sleep(d time.Duration)
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time        { return time.Now() }
+func (realClock) Sleep(d time.Duration) { time.Sleep(d) }
+
+// pollTick is how often Receives and Closes look at their channel.
+const pollTick = time.Millisecond
+
+var clocks sync.Map // testing.TB -> Clock
+
+// UseClock makes the waiting assertions of t measure time on c until t
+// ends. Waiting on a *clock.Fake advances it, making them deterministic.
+// Subtests don't inherit the clock of their parent.
+func UseClock(t testing.TB, c Clock) {
+	clocks.Store(t, c)
+	t.Cleanup(func() { clocks.Delete(t) })
+}
+
+func clockOf(t testing.TB) Clock {
+	if c, ok := clocks.Load(t); ok {
+		return c.(Clock)
+	}
+	return realClock{}
+}
+
+// poll calls f every tick until it returns true or timeout elapses,
+// reporting whether it returned true.
+func poll(c Clock, timeout, tick time.Duration, f func() bool) bool {
+	deadline := c.Now().Add(timeout)
+	for {
+		if f() {
+			return true
+		}
+		left := deadline.Sub(c.Now())
+		if left <= 0 {
+			return false
+		}
+		runtime.Gosched()
+		c.Sleep(min(tick, left))
+	}
+}
+
+func checkEventually(t testing.TB, cond func() bool, timeout, tick time.Duration) string {
+	if poll(clockOf(t), timeout, tick, cond) {
+		return ""
+	}
+	return fmt.Sprintf("expect:condition met within %v\nactual:not met", timeout)
+}
+
+func checkEventuallyValue[T any](t testing.TB, cond func() (T, bool), timeout, tick time.Duration) (T, string) {
+	var last T
+	if poll(clockOf(t), timeout, tick, func() bool {
+		v, ok := cond()
+		last = v
+		return ok
+	}) {
+		return last, ""
+	}
+	return last, fmt.Sprintf("expect:condition met within %v\nactual:last value %s", timeout, formatAny(last))
+}
+
+// checkHolds fails as soon as cond stops returning holds within timeout.
+func checkHolds(t testing.TB, cond func() bool, holds bool, timeout, tick time.Duration) string {
+	if !poll(clockOf(t), timeout, tick, func() bool { return cond() != holds }) {
+		return ""
+	}
+	if holds {
+		return fmt.Sprintf("expect:condition holding for %v\nactual:not met", timeout)
+	}
+	return fmt.Sprintf("expect:condition not met for %v\nactual:met", timeout)
+}
+
+func formatAny(v any) string {
+	return formatValue(addressable(v))
+}
+
+// EventuallyErrorf checks that cond returns true within timeout, calling it
+// every tick.
+func EventuallyErrorf(t testing.TB, cond func() bool, timeout, tick time.Duration, format string, args ...any) {
+	t.Helper()
+	if failure := checkEventually(t, cond, timeout, tick); failure != "" {
+		fail(t, false, failure, format, args...)
+	}
+}
+
+func EventuallyFatalf(t testing.TB, cond func() bool, timeout, tick time.Duration, format string, args ...any) {
+	t.Helper()
+	if failure := checkEventually(t, cond, timeout, tick); failure != "" {
+		fail(t, true, failure, format, args...)
+	}
+}
+
+// EventuallyValueErrorf is EventuallyErrorf for a cond also returning a
+// value, which is shown on failure. It returns the last value cond returned:
+//
+//	n := assert.EventuallyValueErrorf(t, func() (int, bool) {
+//		n := counter.Load()
+//		return n, n == 3
+//	}, time.Second, 10*time.Millisecond, "counter")
+func EventuallyValueErrorf[T any](t testing.TB, cond func() (T, bool), timeout, tick time.Duration, format string, args ...any) T {
+	t.Helper()
+	last, failure := checkEventuallyValue(t, cond, timeout, tick)
+	if failure != "" {
+		fail(t, false, failure, format, args...)
+	}
+	return last
+}
+
+func EventuallyValueFatalf[T any](t testing.TB, cond func() (T, bool), timeout, tick time.Duration, format string, args ...any) T {
+	t.Helper()
+	last, failure := checkEventuallyValue(t, cond, timeout, tick)
+	if failure != "" {
+		fail(t, true, failure, format, args...)
+	}
+	return last
+}
+
+// NeverErrorf checks that cond keeps returning false for timeout, calling
+// it every tick.
+func NeverErrorf(t testing.TB, cond func() bool, timeout, tick time.Duration, format string, args ...any) {
+	t.Helper()
+	if failure := checkHolds(t, cond, false, timeout, tick); failure != "" {
+		fail(t, false, failure, format, args...)
+	}
+}
+
+func NeverFatalf(t testing.TB, cond func() bool, timeout, tick time.Duration, format string, args ...any) {
+	t.Helper()
+	if failure := checkHolds(t, cond, false, timeout, tick); failure != "" {
+		fail(t, true, failure, format, args...)
+	}
+}
+
+// ConsistentlyErrorf checks that cond keeps returning true for timeout,
+// calling it every tick.
+func ConsistentlyErrorf(t testing.TB, cond func() bool, timeout, tick time.Duration, format string, args ...any) {
+	t.Helper()
+	if failure := checkHolds(t, cond, true, timeout, tick); failure != "" {
+		fail(t, false, failure, format, args...)
+	}
+}
+
+func ConsistentlyFatalf(t testing.TB, cond func() bool, timeout, tick time.Duration, format string, args ...any) {
+	t.Helper()
+	if failure := checkHolds(t, cond, true, timeout, tick); failure != "" {
+		fail(t, true, failure, format, args...)
+	}
+}
+
+// receive waits up to timeout for ch to deliver a value or be closed.
+func receive[T any](t testing.TB, ch <-chan T, timeout time.Duration) (v T, ok, received bool) {
+	poll(clockOf(t), timeout, pollTick, func() bool {
+		select {
+		case v, ok = <-ch:
+			received = true
+		default:
+		}
+		return received
+	})
+	return v, ok, received
+}
+
+func checkReceives[T any](t testing.TB, ch <-chan T, timeout time.Duration) (T, string) {
+	v, ok, received := receive(t, ch, timeout)
+	switch {
+	case !received:
+		return v, fmt.Sprintf("expect:value received within %v\nactual:nothing", timeout)
+	case !ok:
+		return v, "expect:value received\nactual:channel closed"
+	}
+	return v, ""
+}
+
+func checkCloses[T any](t testing.TB, ch <-chan T, timeout time.Duration) string {
+	v, ok, received := receive(t, ch, timeout)
+	switch {
+	case !received:
+		return fmt.Sprintf("expect:channel closed within %v\nactual:still open", timeout)
+	case ok:
+		return fmt.Sprintf("expect:channel closed\nactual:received %s", formatAny(v))
+	}
+	return ""
+}
+
+// ReceivesErrorf checks that a value is received from ch within timeout,
+// and returns it.
+func ReceivesErrorf[T any](t testing.TB, ch <-chan T, timeout time.Duration, format string, args ...any) T {
+	t.Helper()
+	v, failure := checkReceives(t, ch, timeout)
+	if failure != "" {
+		fail(t, false, failure, format, args...)
+	}
+	return v
+}
+
+func ReceivesFatalf[T any](t testing.TB, ch <-chan T, timeout time.Duration, format string, args ...any) T {
+	t.Helper()
+	v, failure := checkReceives(t, ch, timeout)
+	if failure != "" {
+		fail(t, true, failure, format, args...)
+	}
+	return v
+}
+
+// ClosesErrorf checks that ch is closed within timeout, without a value
+// being received first.
+func ClosesErrorf[T any](t testing.TB, ch <-chan T, timeout time.Duration, format string, args ...any) {
+	t.Helper()
+	if failure := checkCloses(t, ch, timeout); failure != "" {
+		fail(t, false, failure, format, args...)
+	}
+}
+
+func ClosesFatalf[T any](t testing.TB, ch <-chan T, timeout time.Duration, format string, args ...any) {
+	t.Helper()
+	if failure := checkCloses(t, ch, timeout); failure != "" {
+		fail(t, true, failure, format, args...)
+	}
+}
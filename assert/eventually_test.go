@@ -0,0 +1,89 @@
+package assert
+
+import (
+	"github.com/hyicode/utils/clock"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestEventually(t *testing.T) {
+	clk := clock.NewFake(time.Unix(0, 0))
+	UseClock(t, clk)
+	var n atomic.Int32
+	clk.AfterFunc(30*time.Millisecond, func() { n.Store(3) })
+	done := func() bool { return n.Load() == 3 }
+
+	start := clk.Now()
+	EventuallyFatalf(t, done, time.Second, 10*time.Millisecond, "counter")
+	EqualErrorf(t, 30*time.Millisecond, clk.Now().Sub(start), "waited")
+
+	tb := &fakeTB{TB: t}
+	UseClock(tb, clk)
+	n.Store(1)
+	EventuallyErrorf(tb, done, 50*time.Millisecond, 10*time.Millisecond, "counter")
+	NeverErrorf(tb, func() bool { return n.Load() == 1 }, time.Second, time.Millisecond, "never")
+	ConsistentlyFatalf(tb, func() bool { return n.Load() == 2 }, time.Second, time.Millisecond, "consistently")
+	DeepEqualErrorf(t, []string{
+		"\nexpect:condition met within 50ms\nactual:not met\nmsg:counter\n",
+		"\nexpect:condition not met for 1s\nactual:met\nmsg:never\n",
+	}, tb.errors, "errors")
+	DeepEqualErrorf(t, []string{
+		"\nexpect:condition holding for 1s\nactual:not met\nmsg:consistently\n",
+	}, tb.fatals, "fatals")
+
+	start = clk.Now()
+	NeverErrorf(t, done, time.Second, 100*time.Millisecond, "never")
+	ConsistentlyErrorf(t, func() bool { return true }, time.Second, 100*time.Millisecond, "consistently")
+	EqualErrorf(t, 2*time.Second, clk.Now().Sub(start), "waited")
+}
+
+func TestEventuallyValue(t *testing.T) {
+	clk := clock.NewFake(time.Unix(0, 0))
+	UseClock(t, clk)
+	var n atomic.Int32
+	clk.AfterFunc(30*time.Millisecond, func() { n.Store(3) })
+	counter := func() (int32, bool) {
+		v := n.Load()
+		return v, v == 3
+	}
+	EqualErrorf(t, int32(3), EventuallyValueFatalf(t, counter, time.Second, 10*time.Millisecond, "counter"), "last value")
+
+	tb := &fakeTB{TB: t}
+	UseClock(tb, clk)
+	n.Store(1)
+	EqualErrorf(t, int32(1), EventuallyValueErrorf(tb, counter, 50*time.Millisecond, 10*time.Millisecond, "counter"), "value on failure")
+	DeepEqualErrorf(t, []string{"\nexpect:condition met within 50ms\nactual:last value 1\nmsg:counter\n"}, tb.errors, "errors")
+}
+
+func TestEventuallyRealClock(t *testing.T) {
+	var n atomic.Int32
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		n.Store(1)
+	}()
+	EventuallyErrorf(t, func() bool { return n.Load() == 1 }, 5*time.Second, time.Millisecond, "counter")
+}
+
+func TestReceivesCloses(t *testing.T) {
+	UseClock(t, clock.NewFake(time.Unix(0, 0)))
+	ch := make(chan int, 1)
+	ch <- 7
+	EqualErrorf(t, 7, ReceivesFatalf(t, ch, time.Second, "receive"), "received")
+
+	tb := &fakeTB{TB: t}
+	UseClock(tb, clock.NewFake(time.Unix(0, 0)))
+	ReceivesErrorf(tb, ch, time.Second, "nothing")
+	ClosesErrorf(tb, ch, time.Second, "open")
+	ch <- 8
+	ClosesErrorf(tb, ch, time.Second, "value")
+	close(ch)
+	ClosesErrorf(tb, ch, time.Second, "closed")
+	ReceivesFatalf(tb, ch, time.Second, "closed")
+	DeepEqualErrorf(t, []string{
+		"\nexpect:value received within 1s\nactual:nothing\nmsg:nothing\n",
+		"\nexpect:channel closed within 1s\nactual:still open\nmsg:open\n",
+		"\nexpect:channel closed\nactual:received 8\nmsg:value\n",
+	}, tb.errors, "errors")
+	DeepEqualErrorf(t, []string{"\nexpect:value received\nactual:channel closed\nmsg:closed\n"}, tb.fatals, "fatals")
+}
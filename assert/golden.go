@@ -0,0 +1,213 @@
+package assert
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"unicode/utf8"
+)
+
+// update is namespaced, as test packages commonly declare their own -update
+// flag, which updating honours as well.
+var update = flag.Bool("assert.update", false, "rewrite the golden files of assert.Golden and assert.Snapshot")
+
+// updating reports whether golden files are to be written, with either
+// -assert.update or a boolean -update flag declared by the test package.
+func updating() bool {
+	if *update {
+		return true
+	}
+	f := flag.Lookup("update")
+	if f == nil {
+		return false
+	}
+	g, ok := f.Value.(flag.Getter)
+	if !ok {
+		return false
+	}
+	b, _ := g.Get().(bool)
+	return b
+}
+
+// goldenDir is where golden files are kept, relative to the package
+// directory the tests run in.
+var goldenDir = "testdata"
+
+// goldenDirs holds the directories of the tests that checked golden files,
+// for GoldenMain.
+var goldenDirs sync.Map // string -> bool
+
+type goldenState struct {
+	dir       string
+	snapshots int
+	used      map[string]bool
+}
+
+var goldens sync.Map // testing.TB -> *goldenState
+
+// goldenOf returns the golden files state of t, checking on cleanup that all
+// the golden files of its directory were used.
+func goldenOf(t testing.TB) *goldenState {
+	if s, ok := goldens.Load(t); ok {
+		return s.(*goldenState)
+	}
+	s := &goldenState{dir: filepath.Join(goldenDir, filepath.FromSlash(t.Name())), used: make(map[string]bool)}
+	goldens.Store(t, s)
+	goldenDirs.Store(s.dir, true)
+	t.Cleanup(func() {
+		defer goldens.Delete(t)
+		if t.Failed() || t.Skipped() {
+			return
+		}
+		entries, _ := os.ReadDir(s.dir)
+		for _, e := range entries {
+			if e.IsDir() || !strings.HasSuffix(e.Name(), ".golden") || s.used[e.Name()] {
+				continue
+			}
+			path := filepath.Join(s.dir, e.Name())
+			if updating() {
+				os.Remove(path)
+			} else {
+				t.Errorf("assert: unused golden file %s, run with -assert.update to remove it", path)
+			}
+		}
+	})
+	return s
+}
+
+func checkGolden(t testing.TB, name string, got []byte) string {
+	s := goldenOf(t)
+	file := name + ".golden"
+	s.used[file] = true
+	path := filepath.Join(s.dir, file)
+	if updating() {
+		if err := os.MkdirAll(s.dir, 0o755); err != nil {
+			return err.Error()
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			return err.Error()
+		}
+		return ""
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Sprintf("%v\nrun with -assert.update to create it", err)
+	}
+	if bytes.Equal(want, got) {
+		return ""
+	}
+	if !utf8.Valid(want) || !utf8.Valid(got) {
+		return fmt.Sprintf("%s: binary contents differ, %d bytes expected, %d bytes actual", path, len(want), len(got))
+	}
+	return fmt.Sprintf("%s differs (-golden +actual):\n%s", path, unifiedDiff(string(want), string(got)))
+}
+
+func checkSnapshot(t testing.TB, value any) string {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Sprintf("assert: snapshot: %v", err)
+	}
+	s := goldenOf(t)
+	s.snapshots++
+	return checkGolden(t, fmt.Sprintf("snapshot-%d", s.snapshots), append(data, '\n'))
+}
+
+// GoldenErrorf checks that got matches the file testdata/<test>/<name>.golden.
+// With the -assert.update flag, the file is written instead. Golden files of
+// the test left unused are reported once it ends.
+func GoldenErrorf(t testing.TB, name string, got []byte, format string, args ...any) {
+	t.Helper()
+	if failure := checkGolden(t, name, got); failure != "" {
+		fail(t, false, failure, format, args...)
+	}
+}
+
+func GoldenFatalf(t testing.TB, name string, got []byte, format string, args ...any) {
+	t.Helper()
+	if failure := checkGolden(t, name, got); failure != "" {
+		fail(t, true, failure, format, args...)
+	}
+}
+
+// SnapshotErrorf checks value, encoded as indented JSON, against the next
+// golden file of the test, testdata/<test>/snapshot-<n>.golden. Map keys
+// are sorted, so the encoding is stable.
+func SnapshotErrorf(t testing.TB, value any, format string, args ...any) {
+	t.Helper()
+	if failure := checkSnapshot(t, value); failure != "" {
+		fail(t, false, failure, format, args...)
+	}
+}
+
+func SnapshotFatalf(t testing.TB, value any, format string, args ...any) {
+	t.Helper()
+	if failure := checkSnapshot(t, value); failure != "" {
+		fail(t, true, failure, format, args...)
+	}
+}
+
+// GoldenMain runs the tests of m and then reports the golden files of tests
+// that no longer check them, or removes them with -assert.update. It returns
+// the exit code, for use in TestMain:
+//
+//	func TestMain(m *testing.M) { os.Exit(assert.GoldenMain(m)) }
+//
+// The check is skipped when tests fail or only some of them run, with -run,
+// -skip or -short.
+func GoldenMain(m *testing.M) int {
+	code := m.Run()
+	if code != 0 || testing.Short() || flagSet("test.run") || flagSet("test.skip") {
+		return code
+	}
+	stale, err := staleGoldenFiles(goldenDir, func(dir string) bool {
+		_, ok := goldenDirs.Load(dir)
+		return ok
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "assert: %v\n", err)
+		return 1
+	}
+	for _, path := range stale {
+		if updating() {
+			os.Remove(path)
+			os.Remove(filepath.Dir(path)) // only if left empty
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "assert: stale golden file %s, run with -assert.update to remove it\n", path)
+		code = 1
+	}
+	return code
+}
+
+func flagSet(name string) bool {
+	f := flag.Lookup(name)
+	return f != nil && f.Value.String() != ""
+}
+
+// staleGoldenFiles returns the golden files under root whose directory is
+// not used.
+func staleGoldenFiles(root string, used func(dir string) bool) ([]string, error) {
+	var stale []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) && path == root {
+			return fs.SkipAll
+		}
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && strings.HasSuffix(path, ".golden") && !used(filepath.Dir(path)) {
+			stale = append(stale, path)
+		}
+		return nil
+	})
+	return stale, err
+}
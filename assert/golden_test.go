@@ -0,0 +1,108 @@
+package assert
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type snapshotReport struct {
+	Title  string
+	Counts map[string]int
+	At     time.Time
+}
+
+func TestMain(m *testing.M) { os.Exit(GoldenMain(m)) }
+
+func TestGolden(t *testing.T) {
+	GoldenErrorf(t, "report", []byte("line 1\nline 2\n"), "report")
+	SnapshotErrorf(t, snapshotReport{
+		Title:  "daily",
+		Counts: map[string]int{"b": 2, "a": 1, "c": 3},
+		At:     time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+	}, "report snapshot")
+	SnapshotErrorf(t, []string{"x", "y"}, "second snapshot")
+}
+
+// withGoldenDir runs f with the golden files kept in a temporary directory.
+func withGoldenDir(t *testing.T, updating bool, f func(tb *fakeTB)) *fakeTB {
+	dir, updated := goldenDir, *update
+	goldenDir, *update = t.TempDir(), updating
+	defer func() { goldenDir, *update = dir, updated }()
+
+	var tb *fakeTB
+	t.Run("sub", func(t *testing.T) {
+		tb = &fakeTB{TB: t}
+		f(tb)
+	})
+	return tb
+}
+
+func TestGoldenUpdate(t *testing.T) {
+	var dir string
+	withGoldenDir(t, true, func(tb *fakeTB) {
+		dir = filepath.Join(goldenDir, "TestGoldenUpdate", "sub")
+		os.MkdirAll(dir, 0o755)
+		os.WriteFile(filepath.Join(dir, "stale.golden"), nil, 0o644)
+		GoldenErrorf(tb, "out", []byte("new"), "out")
+		SnapshotErrorf(tb, map[string]int{"b": 1, "a": 2}, "snapshot")
+	})
+	data, err := os.ReadFile(filepath.Join(dir, "out.golden"))
+	NoErrorFatalf(t, err, "read out")
+	EqualErrorf(t, "new", string(data), "out")
+	data, err = os.ReadFile(filepath.Join(dir, "snapshot-1.golden"))
+	NoErrorFatalf(t, err, "read snapshot")
+	EqualErrorf(t, "{\n  \"a\": 2,\n  \"b\": 1\n}\n", string(data), "snapshot")
+	_, err = os.Stat(filepath.Join(dir, "stale.golden"))
+	ErrorIsErrorf(t, err, os.ErrNotExist, "stale file removed")
+}
+
+func TestGoldenFailures(t *testing.T) {
+	tb := withGoldenDir(t, false, func(tb *fakeTB) {
+		dir := filepath.Join(goldenDir, "TestGoldenFailures", "sub")
+		os.MkdirAll(dir, 0o755)
+		os.WriteFile(filepath.Join(dir, "out.golden"), []byte("a\nb\nc\n"), 0o644)
+		os.WriteFile(filepath.Join(dir, "unused.golden"), nil, 0o644)
+		GoldenErrorf(tb, "out", []byte("a\nB\nc\n"), "out")
+		SnapshotFatalf(tb, 1, "missing")
+		SnapshotErrorf(tb, func() {}, "unsupported")
+	})
+	dir := filepath.Join("TestGoldenFailures", "sub")
+	LenFatalf(t, tb.errors, 3, "errors: %q", tb.errors)
+	ContainsErrorf(t, tb.errors[0], dir+"/out.golden differs (-golden +actual):\n@@ -1,4 +1,4 @@\n a\n-b\n+B\n c\n", "diff")
+	ContainsErrorf(t, tb.errors[1], "json: unsupported type: func()", "unsupported")
+	ContainsErrorf(t, tb.errors[2], "assert: unused golden file", "unused")
+	ContainsErrorf(t, tb.errors[2], dir+"/unused.golden", "unused file")
+	LenFatalf(t, tb.fatals, 1, "fatals: %q", tb.fatals)
+	ContainsErrorf(t, tb.fatals[0], "run with -assert.update to create it", "missing")
+}
+
+// testUpdate is the flag test packages commonly declare, which must not
+// clash with the one of assert.
+var testUpdate = flag.Bool("update", false, "update the golden files")
+
+func TestGoldenUpdateFlag(t *testing.T) {
+	defer func(v, u bool) { *testUpdate, *update = v, u }(*testUpdate, *update)
+	*testUpdate, *update = false, false
+	EqualErrorf(t, false, updating(), "unset")
+	*testUpdate = true
+	EqualErrorf(t, true, updating(), "test package -update")
+}
+
+func TestStaleGoldenFiles(t *testing.T) {
+	root := t.TempDir()
+	for _, path := range []string{"TestA/a.golden", "TestA/sub/b.golden", "TestB/c.golden", "TestB/notes.txt"} {
+		path = filepath.Join(root, filepath.FromSlash(path))
+		os.MkdirAll(filepath.Dir(path), 0o755)
+		os.WriteFile(path, nil, 0o644)
+	}
+	stale, err := staleGoldenFiles(root, func(dir string) bool { return dir == filepath.Join(root, "TestA") })
+	NoErrorFatalf(t, err, "walk")
+	DeepEqualErrorf(t, []string{filepath.Join(root, "TestA", "sub", "b.golden"), filepath.Join(root, "TestB", "c.golden")}, stale, "stale")
+
+	stale, err = staleGoldenFiles(filepath.Join(root, "missing"), func(string) bool { return false })
+	NoErrorFatalf(t, err, "missing root")
+	LenErrorf(t, stale, 0, "missing root")
+}
@@ -0,0 +1,307 @@
+package assert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// JSON placeholders match the values of the actual document they stand for
+// in the expected one.
+var jsonPlaceholders = map[string]func(v any) bool{
+	"<any>": func(any) bool { return true },
+	"<uuid>": func(v any) bool {
+		s, ok := v.(string)
+		return ok && uuidPattern.MatchString(s)
+	},
+	"<string>": func(v any) bool {
+		_, ok := v.(string)
+		return ok
+	},
+	"<number>": func(v any) bool {
+		_, ok := v.(json.Number)
+		return ok
+	},
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// IgnoreJSONPaths skips the values at the given JSON pointers in JSONEq
+// comparisons. A "*" segment matches any key or index: "/items/*/id".
+func IgnoreJSONPaths(pointers ...string) Option {
+	return func(o *options) {
+		for _, p := range pointers {
+			o.ignoreJSON = append(o.ignoreJSON, strings.Split(p, "/"))
+		}
+	}
+}
+
+// JSONPlaceholder makes the string token, used as a value in an expected
+// JSON document, match the actual values accepted by match. Strings are
+// given to match as string, numbers as json.Number, objects as
+// map[string]any and arrays as []any. "<any>", "<uuid>", "<string>" and
+// "<number>" are always defined.
+func JSONPlaceholder(token string, match func(v any) bool) Option {
+	return func(o *options) { o.placeholders[token] = match }
+}
+
+func decodeJSON(data string) (any, error) {
+	dec := json.NewDecoder(strings.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err == nil {
+		return nil, fmt.Errorf("data after the JSON value")
+	}
+	return v, nil
+}
+
+// JSONDiff returns the differences between the JSON documents expect and
+// actual, one per line as "pointer: expect != actual", or "" if they are
+// semantically equal: whatever their key order, whitespace and number
+// formatting.
+func (c Comparer) JSONDiff(expect, actual string) (string, error) {
+	ev, err := decodeJSON(expect)
+	if err != nil {
+		return "", fmt.Errorf("expect is not JSON: %w", err)
+	}
+	av, err := decodeJSON(actual)
+	if err != nil {
+		return "", fmt.Errorf("actual is not JSON: %w", err)
+	}
+	d := differ{o: c.o}
+	d.diffJSON(nil, ev, av)
+	return d.String(), nil
+}
+
+func (c Comparer) checkJSONEq(expect, actual string) string {
+	diff, err := c.JSONDiff(expect, actual)
+	if err != nil {
+		return err.Error()
+	}
+	if diff != "" {
+		return "JSON diff (expect != actual):\n" + diff
+	}
+	return ""
+}
+
+var (
+	pointerEscaper   = strings.NewReplacer("~", "~0", "/", "~1")
+	pointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+)
+
+// pointer returns the JSON pointer of path.
+func pointer(path []string) string {
+	var b strings.Builder
+	for _, seg := range path {
+		b.WriteByte('/')
+		b.WriteString(pointerEscaper.Replace(seg))
+	}
+	return b.String()
+}
+
+func child(path []string, seg string) []string {
+	return append(path[:len(path):len(path)], seg)
+}
+
+func formatJSON(v any) string {
+	if v == nil {
+		return "null"
+	}
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.Encode(v)
+	s := strings.TrimSuffix(b.String(), "\n")
+	if len(s) > 2*longString {
+		s = s[:2*longString] + "..."
+	}
+	return s
+}
+
+func (o *options) ignoreJSONPath(path []string) bool {
+	for _, p := range o.ignoreJSON {
+		// p starts with the "" before the first "/"
+		if len(p)-1 != len(path) {
+			continue
+		}
+		match := true
+		for i, seg := range p[1:] {
+			if seg != "*" && pointerUnescaper.Replace(seg) != path[i] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+func (o *options) placeholder(expect, actual any) (match, ok bool) {
+	s, isString := expect.(string)
+	if !isString {
+		return false, false
+	}
+	f := o.placeholders[s]
+	if f == nil {
+		f = jsonPlaceholders[s]
+	}
+	if f == nil {
+		return false, false
+	}
+	return f(actual), true
+}
+
+func (d *differ) diffJSON(path []string, a, b any) {
+	if d.o.ignoreJSONPath(path) {
+		return
+	}
+	p := pointer(path)
+	if match, ok := d.o.placeholder(a, b); ok {
+		if !match {
+			d.report(p, "%s != %s", formatJSON(a), formatJSON(b))
+		}
+		return
+	}
+	switch av := a.(type) {
+	case map[string]any:
+		bv, ok := b.(map[string]any)
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(av)+len(bv))
+		for k := range av {
+			keys = append(keys, k)
+		}
+		for k := range bv {
+			if _, ok := av[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			sub := child(path, k)
+			ae, aok := av[k]
+			be, bok := bv[k]
+			switch {
+			case d.o.ignoreJSONPath(sub):
+			case !aok:
+				d.report(pointer(sub), "<missing> != %s", formatJSON(be))
+			case !bok:
+				d.report(pointer(sub), "%s != <missing>", formatJSON(ae))
+			default:
+				d.diffJSON(sub, ae, be)
+			}
+		}
+		return
+	case []any:
+		bv, ok := b.([]any)
+		if !ok {
+			break
+		}
+		if d.o.unordered {
+			d.diffJSONUnordered(path, av, bv)
+			return
+		}
+		for i := 0; i < max(len(av), len(bv)); i++ {
+			sub := child(path, strconv.Itoa(i))
+			switch {
+			case d.o.ignoreJSONPath(sub):
+			case i >= len(av):
+				d.report(pointer(sub), "<missing> != %s", formatJSON(bv[i]))
+			case i >= len(bv):
+				d.report(pointer(sub), "%s != <missing>", formatJSON(av[i]))
+			default:
+				d.diffJSON(sub, av[i], bv[i])
+			}
+		}
+		return
+	case json.Number:
+		if bv, ok := b.(json.Number); ok && (av == bv || d.numbersEqual(av, bv)) {
+			return
+		}
+	default:
+		if a == b {
+			return
+		}
+	}
+	d.report(p, "%s != %s", formatJSON(a), formatJSON(b))
+}
+
+// numbersEqual compares integer literals exactly, as JSON IDs may not fit
+// a float64, and other numbers as floats, or all of them with FloatTolerance.
+func (d *differ) numbersEqual(a, b json.Number) bool {
+	ai, aok := new(big.Int).SetString(string(a), 10)
+	bi, bok := new(big.Int).SetString(string(b), 10)
+	if aok && bok && ai.Cmp(bi) == 0 {
+		return true
+	}
+	if aok && bok && d.o.floatDelta == 0 {
+		return false
+	}
+	af, aerr := a.Float64()
+	bf, berr := b.Float64()
+	return aerr == nil && berr == nil && d.o.floatEqual(af, bf)
+}
+
+func (d *differ) diffJSONUnordered(path []string, a, b []any) {
+	matched := make([]bool, len(b))
+	for i := range a {
+		found := false
+		for j := 0; j < len(b) && !found; j++ {
+			if matched[j] {
+				continue
+			}
+			sub := differ{o: d.o}
+			sub.diffJSON(child(path, strconv.Itoa(j)), a[i], b[j])
+			if len(sub.diffs) == 0 {
+				matched[j], found = true, true
+			}
+		}
+		if !found {
+			d.report(pointer(child(path, strconv.Itoa(i))), "%s != <missing>", formatJSON(a[i]))
+		}
+	}
+	for j, ok := range matched {
+		if !ok {
+			d.report(pointer(child(path, strconv.Itoa(j))), "<missing> != %s", formatJSON(b[j]))
+		}
+	}
+}
+
+// JSONEqErrorf checks that the JSON documents expect and actual are
+// semantically equal, see Comparer.JSONDiff. Values of expect may be
+// placeholders like "<any>" or "<uuid>", see JSONPlaceholder.
+func JSONEqErrorf(t testing.TB, expect, actual string, format string, args ...any) {
+	t.Helper()
+	With().JSONEqErrorf(t, expect, actual, format, args...)
+}
+
+func JSONEqFatalf(t testing.TB, expect, actual string, format string, args ...any) {
+	t.Helper()
+	With().JSONEqFatalf(t, expect, actual, format, args...)
+}
+
+func (c Comparer) JSONEqErrorf(t testing.TB, expect, actual string, format string, args ...any) {
+	t.Helper()
+	if failure := c.checkJSONEq(expect, actual); failure != "" {
+		fail(t, false, failure, format, args...)
+	}
+}
+
+func (c Comparer) JSONEqFatalf(t testing.TB, expect, actual string, format string, args ...any) {
+	t.Helper()
+	if failure := c.checkJSONEq(expect, actual); failure != "" {
+		fail(t, true, failure, format, args...)
+	}
+}
@@ -0,0 +1,66 @@
+package assert
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestJSONDiff(t *testing.T) {
+	expect := `{"id": "<uuid>", "name": "a", "items": [{"n": 1}, {"n": 2.0}], "meta": {"a/b": 1, "t~": 2}, "at": "<any>"}`
+	actual := `{
+		"name": "a",
+		"id": "123e4567-e89b-12d3-a456-426614174000",
+		"items": [{"n": 1.0}, {"n": 2}],
+		"meta": {"t~": 2, "a/b": 1},
+		"at": 1700000000
+	}`
+	diff, err := With().JSONDiff(expect, actual)
+	NoErrorFatalf(t, err, "diff")
+	EqualErrorf(t, "", diff, "semantically equal")
+
+	actual = `{"id": "nope", "name": "b", "items": [{"n": 1}], "meta": {"a/b": true, "t~": 2, "x": null}}`
+	diff, err = With().JSONDiff(expect, actual)
+	NoErrorFatalf(t, err, "diff")
+	EqualErrorf(t, strings.Join([]string{
+		`/at: "<any>" != <missing>`,
+		`/id: "<uuid>" != "nope"`,
+		`/items/1: {"n":2.0} != <missing>`,
+		`/meta/a~1b: 1 != true`,
+		`/meta/x: <missing> != null`,
+		`/name: "a" != "b"`,
+	}, "\n"), diff, "differences")
+
+	diff, err = With().JSONDiff(`{"id": 9007199254740993, "n": 10}`, `{"id": 9007199254740992, "n": 1e1}`)
+	NoErrorFatalf(t, err, "diff")
+	EqualErrorf(t, "/id: 9007199254740993 != 9007199254740992", diff, "large integers")
+
+	_, err = With().JSONDiff(`{"a":`, `{}`)
+	EqualErrorf(t, "expect is not JSON: unexpected EOF", err.Error(), "invalid expect")
+	_, err = With().JSONDiff(`{}`, `{} {}`)
+	EqualErrorf(t, "actual is not JSON: data after the JSON value", err.Error(), "trailing data")
+}
+
+func TestJSONDiffOptions(t *testing.T) {
+	cmp := With(
+		IgnoreJSONPaths("/items/*/id", "/updated"),
+		JSONPlaceholder("<even>", func(v any) bool {
+			n, ok := v.(json.Number)
+			i, err := n.Int64()
+			return ok && err == nil && i%2 == 0
+		}),
+		UnorderedSlices(),
+		FloatTolerance(0.01),
+	)
+	expect := `{"items": [{"id": 1, "n": "<even>"}, {"id": 2, "n": 3}], "tags": ["a", "b"], "price": 9.99, "updated": 1}`
+	actual := `{"items": [{"id": 7, "n": 3}, {"id": 8, "n": 4}], "tags": ["b", "a"], "price": 9.995}`
+	diff, err := cmp.JSONDiff(expect, actual)
+	NoErrorFatalf(t, err, "diff")
+	EqualErrorf(t, "", diff, "equal with options")
+
+	tb := &fakeTB{TB: t}
+	JSONEqErrorf(tb, `[1, 2]`, `[2, 1]`, "order")
+	cmp.JSONEqFatalf(tb, `{"tags": ["a"]}`, `{"tags": ["a", "c"]}`, "extra")
+	DeepEqualErrorf(t, []string{"\nJSON diff (expect != actual):\n/0: 1 != 2\n/1: 2 != 1\nmsg:order\n"}, tb.errors, "errors")
+	DeepEqualErrorf(t, []string{"\nJSON diff (expect != actual):\n/tags/1: <missing> != \"c\"\nmsg:extra\n"}, tb.fatals, "fatals")
+}
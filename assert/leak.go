@@ -0,0 +1,95 @@
+package assert
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// goroutineSettle is how long NoGoroutineLeaks waits for the goroutines
+// started by a test to end.
+var goroutineSettle = time.Second
+
+// knownGoroutines are stack fragments of goroutines the runtime and the
+// testing package may start during a test.
+var knownGoroutines = []string{
+	"os/signal.signal_recv",
+	"os/signal.loop",
+	"runtime.ensureSigM",
+	"runtime/trace.Start",
+	"testing.(*F).Fuzz",
+}
+
+type goroutine struct {
+	id    string
+	stack string
+}
+
+func goroutines() []goroutine {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+	var gs []goroutine
+	for _, stack := range strings.Split(string(buf), "\n\n") {
+		// "goroutine 18 [chan receive]:"
+		header, _, _ := strings.Cut(stack, "\n")
+		id, _, _ := strings.Cut(strings.TrimPrefix(header, "goroutine "), " ")
+		gs = append(gs, goroutine{id: id, stack: stack})
+	}
+	return gs
+}
+
+// NoGoroutineLeaks reports the goroutines started during t that are still
+// running when it ends, once they were given a moment to settle. Goroutines
+// whose stack contains one of the ignore fragments, like a function name,
+// are let be. Tests calling it must not run in parallel with others.
+func NoGoroutineLeaks(t testing.TB, ignore ...string) {
+	t.Helper()
+	before := make(map[string]bool)
+	for _, g := range goroutines() {
+		before[g.id] = true
+	}
+	ignore = append(ignore, knownGoroutines...)
+
+	t.Cleanup(func() {
+		t.Helper()
+		// leaked goroutines run in real time, whatever the clock of t
+		var leaked []goroutine
+		poll(realClock{}, goroutineSettle, 10*time.Millisecond, func() bool {
+			leaked = leaked[:0]
+			for _, g := range goroutines() {
+				if before[g.id] || containsAny(g.stack, ignore) {
+					continue
+				}
+				leaked = append(leaked, g)
+			}
+			return len(leaked) == 0
+		})
+		if len(leaked) == 0 {
+			return
+		}
+		stacks := make([]string, len(leaked))
+		for i, g := range leaked {
+			stacks[i] = g.stack
+		}
+		fail(t, false, fmt.Sprintf("expect:no goroutine left running\nactual:%d left running after %v:\n\n%s",
+			len(leaked), goroutineSettle, strings.Join(stacks, "\n\n")), "goroutine leak")
+	})
+}
+
+func containsAny(s string, fragments []string) bool {
+	for _, f := range fragments {
+		if strings.Contains(s, f) {
+			return true
+		}
+	}
+	return false
+}
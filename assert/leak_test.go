@@ -0,0 +1,41 @@
+package assert
+
+import (
+	"testing"
+	"time"
+)
+
+func leakingWorker(stop chan struct{}) { <-stop }
+
+func ignoredWorker(stop chan struct{}) { <-stop }
+
+func TestNoGoroutineLeaks(t *testing.T) {
+	settle := goroutineSettle
+	goroutineSettle = 50 * time.Millisecond
+	defer func() { goroutineSettle = settle }()
+
+	stop := make(chan struct{})
+	defer close(stop)
+	var tb *fakeTB
+	t.Run("leak", func(t *testing.T) {
+		tb = &fakeTB{TB: t}
+		NoGoroutineLeaks(tb, "assert.ignoredWorker")
+		go leakingWorker(stop)
+		go ignoredWorker(stop)
+		// settles in time
+		go time.Sleep(10 * time.Millisecond)
+	})
+	LenFatalf(t, tb.errors, 1, "errors: %q", tb.errors)
+	ContainsErrorf(t, tb.errors[0], "actual:1 left running after 50ms", "count")
+	ContainsErrorf(t, tb.errors[0], "assert.leakingWorker", "stack")
+	ContainsErrorf(t, tb.errors[0], "msg:goroutine leak", "message")
+
+	t.Run("no leak", func(t *testing.T) {
+		tb = &fakeTB{TB: t}
+		NoGoroutineLeaks(tb)
+		done := make(chan struct{})
+		go func() { close(done) }()
+		<-done
+	})
+	LenErrorf(t, tb.errors, 0, "errors: %q", tb.errors)
+}
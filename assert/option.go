@@ -0,0 +1,198 @@
+package assert
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+	"unsafe"
+)
+
+// Option relaxes the deep comparisons of a Comparer.
+type Option func(o *options)
+
+type options struct {
+	ignoreFields map[reflect.Type]map[string]bool
+	ignoreKeys   map[reflect.Type]map[any]bool
+	floatDelta   float64
+	unordered    bool
+	timeDelta    time.Duration
+	timeSet      bool
+	equal        map[reflect.Type]func(a, b any) bool
+	ignoreJSON   [][]string
+	placeholders map[string]func(v any) bool
+}
+
+// Comparer compares values deeply, according to its options:
+//
+//	cmp := assert.With(assert.IgnoreFields[User]("UpdatedAt"), assert.FloatTolerance(1e-9))
+//	cmp.DeepEqualErrorf(t, expect, actual, "user")
+type Comparer struct {
+	o *options
+}
+
+func With(opts ...Option) Comparer {
+	o := &options{
+		ignoreFields: make(map[reflect.Type]map[string]bool),
+		ignoreKeys:   make(map[reflect.Type]map[any]bool),
+		equal:        make(map[reflect.Type]func(a, b any) bool),
+		placeholders: make(map[string]func(v any) bool),
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return Comparer{o: o}
+}
+
+// IgnoreFields skips the named fields of the struct type T. It panics if T
+// has no such field.
+func IgnoreFields[T any](names ...string) Option {
+	typ := reflect.TypeFor[T]()
+	for _, name := range names {
+		if typ.Kind() != reflect.Struct {
+			panic(fmt.Sprintf("assert: IgnoreFields: %v is not a struct", typ))
+		}
+		if _, ok := typ.FieldByName(name); !ok {
+			panic(fmt.Sprintf("assert: IgnoreFields: %v has no field %s", typ, name))
+		}
+	}
+	return func(o *options) {
+		if o.ignoreFields[typ] == nil {
+			o.ignoreFields[typ] = make(map[string]bool)
+		}
+		for _, name := range names {
+			o.ignoreFields[typ][name] = true
+		}
+	}
+}
+
+// IgnoreMapKeys skips the given keys of the maps keyed by K.
+func IgnoreMapKeys[K comparable](keys ...K) Option {
+	typ := reflect.TypeFor[K]()
+	return func(o *options) {
+		if o.ignoreKeys[typ] == nil {
+			o.ignoreKeys[typ] = make(map[any]bool)
+		}
+		for _, k := range keys {
+			o.ignoreKeys[typ][k] = true
+		}
+	}
+}
+
+// FloatTolerance makes floats equal when they differ by at most delta.
+func FloatTolerance(delta float64) Option {
+	return func(o *options) { o.floatDelta = delta }
+}
+
+// UnorderedSlices compares slices and arrays as multisets.
+func UnorderedSlices() Option {
+	return func(o *options) { o.unordered = true }
+}
+
+// TimeTolerance makes time.Time values equal when they are at most delta
+// apart, whatever their location and monotonic reading.
+func TimeTolerance(delta time.Duration) Option {
+	return func(o *options) {
+		o.timeDelta = delta
+		o.timeSet = true
+	}
+}
+
+// EqualFunc compares the values of type T with equal instead of deeply.
+func EqualFunc[T any](equal func(a, b T) bool) Option {
+	return func(o *options) {
+		o.equal[reflect.TypeFor[T]()] = func(a, b any) bool { return equal(a.(T), b.(T)) }
+	}
+}
+
+var timeType = reflect.TypeFor[time.Time]()
+
+// Diff is the Diff function with c's options.
+func (c Comparer) Diff(expect, actual any) string {
+	d := differ{o: c.o, visited: make(map[visit]bool)}
+	d.diff("", addressable(expect), addressable(actual))
+	return d.String()
+}
+
+func (c Comparer) DeepEqualErrorf(t testing.TB, expect, actual any, format string, args ...any) {
+	t.Helper()
+	if diff := c.Diff(expect, actual); diff != "" {
+		fail(t, false, "diff (expect != actual):\n"+diff, format, args...)
+	}
+}
+
+func (c Comparer) DeepEqualFatalf(t testing.TB, expect, actual any, format string, args ...any) {
+	t.Helper()
+	if diff := c.Diff(expect, actual); diff != "" {
+		fail(t, true, "diff (expect != actual):\n"+diff, format, args...)
+	}
+}
+
+// addressable returns x in an addressable value, so that the values of its
+// unexported fields can be turned back into interfaces by interfaceOf.
+func addressable(x any) reflect.Value {
+	v := reflect.ValueOf(x)
+	if !v.IsValid() {
+		return v
+	}
+	c := reflect.New(v.Type()).Elem()
+	c.Set(v)
+	return c
+}
+
+func interfaceOf(v reflect.Value) (any, bool) {
+	switch {
+	case v.CanInterface():
+		return v.Interface(), true
+	case v.CanAddr():
+		return reflect.NewAt(v.Type(), unsafe.Pointer(v.UnsafeAddr())).Elem().Interface(), true
+	}
+	return nil, false
+}
+
+// custom compares a and b, of the same type, with an option replacing deep
+// equality. ok is false if none applies.
+func (o *options) custom(a, b reflect.Value) (equal, ok bool) {
+	if o == nil {
+		return false, false
+	}
+	f := o.equal[a.Type()]
+	if f == nil && !(o.timeSet && a.Type() == timeType) {
+		return false, false
+	}
+	ai, aok := interfaceOf(a)
+	bi, bok := interfaceOf(b)
+	if !aok || !bok {
+		return false, false
+	}
+	if f != nil {
+		return f(ai, bi), true
+	}
+	d := ai.(time.Time).Sub(bi.(time.Time))
+	return d <= o.timeDelta && d >= -o.timeDelta, true
+}
+
+func (o *options) floatEqual(a, b float64) bool {
+	return a == b || o != nil && math.Abs(a-b) <= o.floatDelta
+}
+
+func (o *options) ignoreField(typ reflect.Type, name string) bool {
+	return o != nil && o.ignoreFields[typ][name]
+}
+
+func (o *options) ignoreKey(k reflect.Value) bool {
+	if o == nil || o.ignoreKeys[k.Type()] == nil {
+		return false
+	}
+	if ki, ok := interfaceOf(k); ok {
+		return o.ignoreKeys[k.Type()][ki]
+	}
+	// keys of maps reached through unexported fields
+	for ki := range o.ignoreKeys[k.Type()] {
+		if formatValue(reflect.ValueOf(ki)) == formatValue(k) {
+			return true
+		}
+	}
+	return false
+}
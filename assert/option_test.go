@@ -0,0 +1,59 @@
+package assert
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type reading struct {
+	Sensor string
+	Value  float64
+	At     time.Time
+	labels map[string]string
+	seen   time.Time
+}
+
+func TestComparerOptions(t *testing.T) {
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	expect := reading{Sensor: "t1", Value: 0.3, At: at, labels: map[string]string{"room": "a", "id": "1"}, seen: at}
+	actual := reading{
+		Sensor: "T1",
+		Value:  0.1 + 0.2,
+		At:     at.Add(time.Millisecond).In(time.FixedZone("x", 3600)),
+		labels: map[string]string{"room": "a", "id": "2"},
+		seen:   at.Add(time.Millisecond),
+	}
+	EqualErrorf(t, 5, len(strings.Split(Diff(expect, actual), "\n")), "without options:\n%s", Diff(expect, actual))
+
+	cmp := With(
+		IgnoreFields[reading]("Sensor"),
+		IgnoreMapKeys("id"),
+		FloatTolerance(1e-9),
+		TimeTolerance(time.Second),
+	)
+	EqualErrorf(t, "", cmp.Diff(expect, actual), "with options")
+
+	cmp = With(TimeTolerance(time.Microsecond), EqualFunc(strings.EqualFold), IgnoreMapKeys("id"), FloatTolerance(1))
+	EqualErrorf(t, strings.Join([]string{
+		".At: time.Date(2024, time.January, 2, 3, 4, 5, 0, time.UTC) != time.Date(2024, time.January, 2, 4, 4, 5, 1000000, time.Location(\"x\"))",
+		".seen: time.Date(2024, time.January, 2, 3, 4, 5, 0, time.UTC) != time.Date(2024, time.January, 2, 3, 4, 5, 1000000, time.UTC)",
+	}, "\n"), cmp.Diff(expect, actual), "tight time tolerance")
+}
+
+func TestComparerUnordered(t *testing.T) {
+	cmp := With(UnorderedSlices())
+	EqualErrorf(t, "", cmp.Diff([]int{1, 2, 2, 3}, []int{2, 3, 1, 2}), "same multiset")
+	EqualErrorf(t, "[2]: 2 != <missing>\n[3]: <missing> != 4", cmp.Diff([]int{1, 2, 2, 3}, []int{2, 3, 1, 4}), "different multisets")
+	EqualErrorf(t, "", cmp.Diff(
+		[]item{{Name: "a", Tags: []string{"x", "y"}}, {Name: "b"}},
+		[]item{{Name: "b"}, {Name: "a", Tags: []string{"y", "x"}}},
+	), "nested")
+}
+
+func TestIgnoreFieldsPanics(t *testing.T) {
+	defer func() {
+		EqualErrorf(t, "assert: IgnoreFields: assert.reading has no field Missing", recover(), "panic")
+	}()
+	IgnoreFields[reading]("Missing")
+}
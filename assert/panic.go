@@ -0,0 +1,65 @@
+package assert
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func checkPanics(f func()) (recovered any, failure string) {
+	panicked := true
+	func() {
+		defer func() { recovered = recover() }()
+		f()
+		panicked = false
+	}()
+	if !panicked {
+		return nil, "expect:panic\nactual:returned"
+	}
+	return recovered, ""
+}
+
+func checkPanicsWith(expect any, f func()) string {
+	recovered, failure := checkPanics(f)
+	if failure != "" {
+		return fmt.Sprintf("expect:panic with %s\nactual:returned", formatValue(reflect.ValueOf(expect)))
+	}
+	if diff := Diff(expect, recovered); diff != "" {
+		return "panic value diff (expect != actual):\n" + diff
+	}
+	return ""
+}
+
+// PanicsErrorf checks that f panics, and returns the value it panicked with.
+func PanicsErrorf(t testing.TB, f func(), format string, args ...any) any {
+	t.Helper()
+	recovered, failure := checkPanics(f)
+	if failure != "" {
+		fail(t, false, failure, format, args...)
+	}
+	return recovered
+}
+
+func PanicsFatalf(t testing.TB, f func(), format string, args ...any) any {
+	t.Helper()
+	recovered, failure := checkPanics(f)
+	if failure != "" {
+		fail(t, true, failure, format, args...)
+	}
+	return recovered
+}
+
+// PanicsWithErrorf checks that f panics with a value deeply equal to expect.
+func PanicsWithErrorf(t testing.TB, expect any, f func(), format string, args ...any) {
+	t.Helper()
+	if failure := checkPanicsWith(expect, f); failure != "" {
+		fail(t, false, failure, format, args...)
+	}
+}
+
+func PanicsWithFatalf(t testing.TB, expect any, f func(), format string, args ...any) {
+	t.Helper()
+	if failure := checkPanicsWith(expect, f); failure != "" {
+		fail(t, true, failure, format, args...)
+	}
+}
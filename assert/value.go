@@ -0,0 +1,110 @@
+package assert
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// isNil reports whether v is nil or holds a nil pointer, map, slice,
+// channel, function or interface.
+func isNil(v any) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Chan, reflect.Func, reflect.Interface, reflect.UnsafePointer:
+		return rv.IsNil()
+	}
+	return false
+}
+
+func checkNil(v any) string {
+	if isNil(v) {
+		return ""
+	}
+	return fmt.Sprintf("expect:nil\nactual:%s", formatValue(reflect.ValueOf(v)))
+}
+
+func checkNotNil(v any) string {
+	if !isNil(v) {
+		return ""
+	}
+	return fmt.Sprintf("expect:not nil\nactual:%s", formatValue(reflect.ValueOf(v)))
+}
+
+func checkZero(v any) string {
+	if v == nil || reflect.ValueOf(v).IsZero() {
+		return ""
+	}
+	return fmt.Sprintf("expect:zero value\nactual:%s", formatValue(reflect.ValueOf(v)))
+}
+
+func checkInDelta(expect, actual, delta float64) string {
+	if math.Abs(expect-actual) <= delta {
+		return ""
+	}
+	return fmt.Sprintf("expect:%v ± %v\nactual:%v (off by %v)", expect, delta, actual, math.Abs(expect-actual))
+}
+
+// NilErrorf checks that v is nil, or a nil pointer, map, slice, channel or
+// function.
+func NilErrorf(t testing.TB, v any, format string, args ...any) {
+	t.Helper()
+	if failure := checkNil(v); failure != "" {
+		fail(t, false, failure, format, args...)
+	}
+}
+
+func NilFatalf(t testing.TB, v any, format string, args ...any) {
+	t.Helper()
+	if failure := checkNil(v); failure != "" {
+		fail(t, true, failure, format, args...)
+	}
+}
+
+func NotNilErrorf(t testing.TB, v any, format string, args ...any) {
+	t.Helper()
+	if failure := checkNotNil(v); failure != "" {
+		fail(t, false, failure, format, args...)
+	}
+}
+
+func NotNilFatalf(t testing.TB, v any, format string, args ...any) {
+	t.Helper()
+	if failure := checkNotNil(v); failure != "" {
+		fail(t, true, failure, format, args...)
+	}
+}
+
+// ZeroErrorf checks that v is nil or the zero value of its type.
+func ZeroErrorf(t testing.TB, v any, format string, args ...any) {
+	t.Helper()
+	if failure := checkZero(v); failure != "" {
+		fail(t, false, failure, format, args...)
+	}
+}
+
+func ZeroFatalf(t testing.TB, v any, format string, args ...any) {
+	t.Helper()
+	if failure := checkZero(v); failure != "" {
+		fail(t, true, failure, format, args...)
+	}
+}
+
+// InDeltaErrorf checks that actual is at most delta away from expect.
+func InDeltaErrorf(t testing.TB, expect, actual, delta float64, format string, args ...any) {
+	t.Helper()
+	if failure := checkInDelta(expect, actual, delta); failure != "" {
+		fail(t, false, failure, format, args...)
+	}
+}
+
+func InDeltaFatalf(t testing.TB, expect, actual, delta float64, format string, args ...any) {
+	t.Helper()
+	if failure := checkInDelta(expect, actual, delta); failure != "" {
+		fail(t, true, failure, format, args...)
+	}
+}
@@ -0,0 +1,173 @@
+package clock
+
+import (
+	"context"
+	"github.com/hyicode/utils/container"
+	"sync"
+	"time"
+)
+
+// Clock is the source of time for code that needs to be tested without
+// sleeping.
+type Clock interface {
+	Now() time.Time
+	AfterFunc(d time.Duration, f func()) Timer
+	Sleep(d time.Duration)
+	// SleepContext is Sleep returning ctx.Err() early once ctx is done.
+	SleepContext(ctx context.Context, d time.Duration) error
+}
+
+type Timer interface {
+	Stop() bool
+}
+
+// Real is the Clock backed by package time.
+var Real Clock = realClock{}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) AfterFunc(d time.Duration, f func()) Timer { return time.AfterFunc(d, f) }
+
+func (realClock) Sleep(d time.Duration) { time.Sleep(d) }
+
+func (realClock) SleepContext(ctx context.Context, d time.Duration) error {
+	if err := ctx.Err(); err != nil || d <= 0 {
+		return err
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Fake is a Clock that only moves when Advance, Set or Sleep is called.
+// Functions due in the meantime run on the goroutine moving the clock, in
+// order of their due time, and observe Now() as that due time.
+type Fake struct {
+	mu     sync.Mutex
+	now    time.Time
+	seq    uint64
+	live   int // timers neither stopped nor run
+	timers container.Heap[*fakeTimer]
+}
+
+func NewFake(now time.Time) *Fake {
+	c := &Fake{now: now}
+	c.timers.Init()
+	return c
+}
+
+type fakeTimer struct {
+	c       *Fake
+	due     time.Time
+	seq     uint64
+	f       func()
+	stopped bool
+}
+
+func (t *fakeTimer) Less(o container.HeapElement) bool {
+	other := o.(*fakeTimer)
+	if t.due.Equal(other.due) {
+		return t.seq < other.seq
+	}
+	return t.due.Before(other.due)
+}
+
+// Stop reports whether the call prevented f from running.
+func (t *fakeTimer) Stop() bool {
+	t.c.mu.Lock()
+	defer t.c.mu.Unlock()
+	if t.stopped {
+		return false
+	}
+	t.stopped = true
+	t.c.live--
+	return true
+}
+
+func (c *Fake) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *Fake) AfterFunc(d time.Duration, f func()) Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.seq++
+	t := &fakeTimer{c: c, due: c.now.Add(d), seq: c.seq, f: f}
+	c.timers.Push(t)
+	c.live++
+	return t
+}
+
+// Sleep advances the clock by d, since nothing else would.
+func (c *Fake) Sleep(d time.Duration) {
+	c.Advance(d)
+}
+
+// SleepContext advances the clock by d like Sleep, unless ctx is already
+// done.
+func (c *Fake) SleepContext(ctx context.Context, d time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	c.Advance(d)
+	return ctx.Err()
+}
+
+func (c *Fake) Advance(d time.Duration) {
+	c.Set(c.Now().Add(d))
+}
+
+// Set moves the clock to now, running every function due until then. The
+// clock never goes backwards.
+func (c *Fake) Set(now time.Time) {
+	for {
+		c.mu.Lock()
+		t := c.next(now)
+		if t == nil {
+			if now.After(c.now) {
+				c.now = now
+			}
+			c.mu.Unlock()
+			return
+		}
+		if t.due.After(c.now) {
+			c.now = t.due
+		}
+		c.mu.Unlock()
+		t.f()
+	}
+}
+
+// Pending returns the number of functions waiting to run.
+func (c *Fake) Pending() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.live
+}
+
+// next pops the earliest live timer due at or before now. c.mu must be held.
+func (c *Fake) next(now time.Time) *fakeTimer {
+	for c.timers.Len() > 0 {
+		t := c.timers.Pop()
+		if t.stopped {
+			continue
+		}
+		if t.due.After(now) {
+			c.timers.Push(t)
+			return nil
+		}
+		t.stopped = true
+		c.live--
+		return t
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package clock
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestFake(t *testing.T) {
+	start := time.Unix(1000, 0)
+	c := NewFake(start)
+
+	var fired []time.Duration
+	at := func(d time.Duration) func() {
+		return func() {
+			if got := c.Now().Sub(start); got != d {
+				t.Errorf("fired at %v, want %v", got, d)
+			}
+			fired = append(fired, d)
+		}
+	}
+	c.AfterFunc(30*time.Millisecond, at(30*time.Millisecond))
+	c.AfterFunc(10*time.Millisecond, at(10*time.Millisecond))
+	stopped := c.AfterFunc(20*time.Millisecond, at(20*time.Millisecond))
+	c.AfterFunc(10*time.Millisecond, func() {
+		c.AfterFunc(5*time.Millisecond, at(15*time.Millisecond))
+	})
+	if n := c.Pending(); n != 4 {
+		t.Fatalf("pending %d, want 4", n)
+	}
+	if !stopped.Stop() || stopped.Stop() {
+		t.Fatal("Stop should report true once")
+	}
+
+	c.Advance(25 * time.Millisecond)
+	if got := c.Now().Sub(start); got != 25*time.Millisecond {
+		t.Fatalf("now %v, want 25ms", got)
+	}
+	c.Sleep(time.Second)
+
+	want := []time.Duration{10 * time.Millisecond, 15 * time.Millisecond, 30 * time.Millisecond}
+	if len(fired) != len(want) {
+		t.Fatalf("fired %v, want %v", fired, want)
+	}
+	for i := range want {
+		if fired[i] != want[i] {
+			t.Errorf("fired %v, want %v", fired, want)
+		}
+	}
+	if n := c.Pending(); n != 0 {
+		t.Errorf("pending %d, want 0", n)
+	}
+}
+
+func TestSleepContext(t *testing.T) {
+	start := time.Unix(1000, 0)
+	c := NewFake(start)
+	if err := c.SleepContext(context.Background(), time.Hour); err != nil {
+		t.Fatalf("fake sleep: %v", err)
+	}
+	if got := c.Now().Sub(start); got != time.Hour {
+		t.Errorf("fake advanced %v, want 1h", got)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := c.SleepContext(ctx, time.Hour); err != context.Canceled {
+		t.Errorf("fake sleep cancelled: %v", err)
+	}
+	if got := c.Now().Sub(start); got != time.Hour {
+		t.Errorf("cancelled fake sleep advanced to %v", got)
+	}
+
+	if err := Real.SleepContext(context.Background(), time.Millisecond); err != nil {
+		t.Errorf("real sleep: %v", err)
+	}
+	ctx, cancel = context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	if err := Real.SleepContext(ctx, time.Hour); err != context.DeadlineExceeded {
+		t.Errorf("real sleep cancelled: %v", err)
+	}
+}
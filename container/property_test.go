@@ -0,0 +1,188 @@
+package container
+
+import (
+	"fmt"
+	"github.com/hyicode/utils/quick"
+	"slices"
+	"testing"
+)
+
+// op is a random operation on a container: Kind picks the operation, Value
+// is the value it adds and At the element it works on, modulo the length.
+type op struct {
+	Kind  int
+	Value int
+	At    int
+}
+
+func ops(kinds int) quick.Gen[[]op] {
+	return quick.SliceOf(quick.Struct[op](map[string]any{
+		"Kind":  quick.Int(0, kinds-1),
+		"Value": quick.Int(-50, 50),
+		"At":    quick.Int(0, 1000),
+	}))
+}
+
+func rangeValues[T any](r func(f func(v T) bool)) []T {
+	var vs []T
+	r(func(v T) bool {
+		vs = append(vs, v)
+		return false
+	})
+	return vs
+}
+
+func TestHeapProperty(t *testing.T) {
+	quick.Check(t, ops(3), func(ops []op) error {
+		h := NewHeap[Int]()
+		var model []Int
+		for i, o := range ops {
+			switch {
+			case o.Kind == 0 || len(model) == 0:
+				h.Push(Int(o.Value))
+				model = append(model, Int(o.Value))
+			case o.Kind == 1:
+				slices.Sort(model)
+				if v := h.Pop(); v != model[0] {
+					return fmt.Errorf("op %d: popped %d, want the min %d", i, v, model[0])
+				}
+				model = model[1:]
+			default:
+				v := h.Remove(o.At % len(model))
+				j := slices.Index(model, v)
+				if j < 0 {
+					return fmt.Errorf("op %d: removed %d, never pushed", i, v)
+				}
+				model = slices.Delete(model, j, j+1)
+			}
+			if h.Len() != len(model) {
+				return fmt.Errorf("op %d: len %d, want %d", i, h.Len(), len(model))
+			}
+		}
+		slices.Sort(model)
+		for _, want := range model {
+			if v := h.Pop(); v != want {
+				return fmt.Errorf("drain: popped %d, want %d", v, want)
+			}
+		}
+		return nil
+	})
+}
+
+func TestStackProperty(t *testing.T) {
+	quick.Check(t, ops(2), func(ops []op) error {
+		s := NewStack[int]()
+		var model []int
+		for i, o := range ops {
+			if o.Kind == 0 || len(model) == 0 {
+				s.Push(o.Value)
+				model = append(model, o.Value)
+			} else {
+				want := model[len(model)-1]
+				model = model[:len(model)-1]
+				if v := s.Pop(); v != want {
+					return fmt.Errorf("op %d: popped %d, want the last pushed %d", i, v, want)
+				}
+			}
+			if s.Len() != len(model) {
+				return fmt.Errorf("op %d: len %d, want %d", i, s.Len(), len(model))
+			}
+			if vs := rangeValues(s.Range); !slices.Equal(vs, model) {
+				return fmt.Errorf("op %d: range %v, want %v", i, vs, model)
+			}
+		}
+		return nil
+	})
+}
+
+func TestListProperty(t *testing.T) {
+	quick.Check(t, ops(6), func(ops []op) error {
+		l := NewList[int]()
+		// elems mirrors the elements of l in order
+		var elems []*Element[int]
+		for i, o := range ops {
+			kind := o.Kind
+			if len(elems) == 0 {
+				kind %= 2
+			}
+			switch kind {
+			case 0:
+				elems = slices.Insert(elems, 0, l.PushFront(o.Value))
+			case 1:
+				elems = append(elems, l.PushBack(o.Value))
+			case 2:
+				j := o.At % len(elems)
+				elems = slices.Insert(elems, j+1, l.InsertAfter(o.Value, elems[j]))
+			case 3:
+				j := o.At % len(elems)
+				l.Remove(elems[j])
+				elems = slices.Delete(elems, j, j+1)
+			case 4:
+				j := o.At % len(elems)
+				e := elems[j]
+				l.MoveToFront(e)
+				elems = slices.Insert(slices.Delete(elems, j, j+1), 0, e)
+			case 5:
+				j := o.At % len(elems)
+				e := elems[j]
+				l.MoveToBack(e)
+				elems = append(slices.Delete(elems, j, j+1), e)
+			}
+			if l.Len() != len(elems) {
+				return fmt.Errorf("op %d: len %d, want %d", i, l.Len(), len(elems))
+			}
+			var forward []*Element[int]
+			for e := l.Front(); e != nil; e = e.Next() {
+				forward = append(forward, e)
+			}
+			var backward []*Element[int]
+			for e := l.Back(); e != nil; e = e.Prev() {
+				backward = append(backward, e)
+			}
+			slices.Reverse(backward)
+			if !slices.Equal(forward, elems) || !slices.Equal(backward, elems) {
+				return fmt.Errorf("op %d: elements out of order", i)
+			}
+			want := make([]int, len(elems))
+			for j, e := range elems {
+				want[j] = e.Value
+			}
+			if vs := rangeValues(l.Range); !slices.Equal(vs, want) {
+				return fmt.Errorf("op %d: range %v, want %v", i, vs, want)
+			}
+		}
+		return nil
+	})
+}
+
+func TestSetProperty(t *testing.T) {
+	quick.Check(t, ops(2), func(ops []op) error {
+		s := NewSet[int]()
+		model := make(map[int]bool)
+		for i, o := range ops {
+			if o.Kind == 0 {
+				s.Add(o.Value)
+				model[o.Value] = true
+			} else {
+				s.Remove(o.Value)
+				delete(model, o.Value)
+			}
+			if s.Len() != len(model) {
+				return fmt.Errorf("op %d: len %d, want %d", i, s.Len(), len(model))
+			}
+			if s.Has(o.Value) != model[o.Value] {
+				return fmt.Errorf("op %d: has %d is %v", i, o.Value, s.Has(o.Value))
+			}
+			vs := rangeValues(s.Range)
+			if len(vs) != len(model) {
+				return fmt.Errorf("op %d: ranged %d values, want %d", i, len(vs), len(model))
+			}
+			for _, v := range vs {
+				if !model[v] {
+					return fmt.Errorf("op %d: ranged %d, not in the set", i, v)
+				}
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,306 @@
+package fsm
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hyicode/utils/trigger"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type State string
+
+var ErrInvalidTransition = errors.New("fsm: invalid transition")
+
+// TransitionError is returned when no transition of the current state
+// accepts an event. It matches ErrInvalidTransition with errors.Is.
+type TransitionError struct {
+	Machine string
+	State   State
+	Event   string
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("fsm: %s: no transition from %q on %q", e.Machine, e.State, e.Event)
+}
+
+func (e *TransitionError) Unwrap() error { return ErrInvalidTransition }
+
+// StateEvent is published on the machine's table when it leaves and enters
+// a state.
+type StateEvent struct {
+	Machine string
+	From    State
+	To      State
+	Event   string // key of the event causing the transition
+	Payload any
+}
+
+// Transition moves a machine from From to To when Event is fired and Guard,
+// if any, accepts the payload. Action, if any, runs before the state
+// changes; an error aborts the transition.
+type Transition[T any] struct {
+	From   State
+	To     State
+	Event  trigger.EventName[T]
+	Guard  func(event T) bool
+	Action func(event T) error
+}
+
+type transition struct {
+	from, to State
+	event    string
+	guarded  bool
+	guard    func(event any) bool
+	action   func(event any) error
+	listen   func(m *Machine) (remove func())
+}
+
+// Definition declares the states and transitions of a kind of machine. It
+// must not be changed once machines are running.
+type Definition struct {
+	name        string
+	initial     State
+	states      []State
+	transitions []*transition
+	byState     map[State][]*transition
+}
+
+func NewDefinition(name string, initial State) *Definition {
+	d := &Definition{name: name, initial: initial, byState: make(map[State][]*transition)}
+	d.addState(initial)
+	return d
+}
+
+func (d *Definition) Name() string { return d.name }
+
+// States returns the states in the order they were declared.
+func (d *Definition) States() []State {
+	return append([]State(nil), d.states...)
+}
+
+func (d *Definition) addState(s State) {
+	for _, v := range d.states {
+		if v == s {
+			return
+		}
+	}
+	d.states = append(d.states, s)
+}
+
+// Add declares tr on d. Transitions of a state are tried in the order they
+// were added, the first one whose guard passes wins.
+func Add[T any](d *Definition, tr Transition[T]) {
+	t := &transition{
+		from:    tr.From,
+		to:      tr.To,
+		event:   string(tr.Event),
+		guarded: tr.Guard != nil,
+		guard: func(event any) bool {
+			return tr.Guard == nil || tr.Guard(event.(T))
+		},
+		action: func(event any) error {
+			if tr.Action == nil {
+				return nil
+			}
+			return tr.Action(event.(T))
+		},
+		listen: func(m *Machine) (remove func()) {
+			remove, _ = tr.Event.OnRemovable(m.table, func(event T) {
+				Fire(m, tr.Event, event)
+			})
+			return remove
+		},
+	}
+	d.addState(tr.From)
+	d.addState(tr.To)
+	d.transitions = append(d.transitions, t)
+	d.byState[tr.From] = append(d.byState[tr.From], t)
+}
+
+// Enter is the event published on a machine's table when it enters s.
+func (d *Definition) Enter(s State) trigger.EventName[StateEvent] {
+	return trigger.EventName[StateEvent]("fsm." + d.name + ".enter." + string(s))
+}
+
+// Exit is the event published on a machine's table when it leaves s.
+func (d *Definition) Exit(s State) trigger.EventName[StateEvent] {
+	return trigger.EventName[StateEvent]("fsm." + d.name + ".exit." + string(s))
+}
+
+// Transitions returns the events accepted in s, sorted.
+func (d *Definition) Transitions(s State) []string {
+	var keys []string
+	for _, t := range d.byState[s] {
+		keys = append(keys, t.event)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// DOT returns the definition as a Graphviz diagram.
+func (d *Definition) DOT() string {
+	return d.dot("")
+}
+
+func (d *Definition) dot(current State) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %q {\n", d.name)
+	b.WriteString("\trankdir=LR;\n")
+	b.WriteString("\t\"\" [shape=point];\n")
+	for _, s := range d.states {
+		style := "rounded"
+		if s == current {
+			style += ",bold"
+		}
+		fmt.Fprintf(&b, "\t%q [shape=box, style=%q];\n", string(s), style)
+	}
+	fmt.Fprintf(&b, "\t\"\" -> %q;\n", string(d.initial))
+	for _, t := range d.transitions {
+		label := t.event
+		if t.guarded {
+			label += " [guarded]"
+		}
+		fmt.Fprintf(&b, "\t%q -> %q [label=%q];\n", string(t.from), string(t.to), label)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+type fired struct {
+	event   string
+	payload any
+}
+
+// Machine is an instance of a Definition publishing its state changes on a
+// table. Events fired by handlers and actions while a transition is running
+// are queued and handled once it completes, so a machine must be driven
+// from one goroutine at a time.
+type Machine struct {
+	def   *Definition
+	id    string
+	table trigger.EventTableI
+
+	mu    sync.Mutex
+	state State
+	busy  bool
+	queue []fired
+}
+
+// NewMachine returns a machine in the initial state of d.
+func (d *Definition) NewMachine(id string, table trigger.EventTableI) *Machine {
+	return &Machine{def: d, id: id, table: table, state: d.initial}
+}
+
+func (m *Machine) ID() string { return m.id }
+
+func (m *Machine) Definition() *Definition { return m.def }
+
+// DOT returns the definition of m as a Graphviz diagram, its current state
+// in bold.
+func (m *Machine) DOT() string {
+	return m.def.dot(m.State())
+}
+
+func (m *Machine) State() State {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.state
+}
+
+// Can reports whether an event of key is accepted in the current state,
+// guards aside.
+func (m *Machine) Can(key string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, t := range m.def.byState[m.state] {
+		if t.event == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Listen makes the events of the definition triggered on the machine's table
+// drive the machine. Invalid transitions are ignored. remove undoes it.
+func (m *Machine) Listen() (remove func()) {
+	seen := make(map[string]bool)
+	var removes []func()
+	for _, t := range m.def.transitions {
+		if seen[t.event] {
+			continue
+		}
+		seen[t.event] = true
+		removes = append(removes, t.listen(m))
+	}
+	return func() {
+		for _, r := range removes {
+			r()
+		}
+	}
+}
+
+// Fire feeds event to m. It returns a *TransitionError when the current
+// state has no transition accepting it, or the error of the transition's
+// action. Called from a handler or action of m, the event is queued and
+// Fire returns nil; the outermost Fire returns the errors of queued events.
+func Fire[T any](m *Machine, e trigger.EventName[T], event T) error {
+	return m.fire(string(e), event)
+}
+
+func (m *Machine) fire(key string, payload any) error {
+	m.mu.Lock()
+	if m.busy {
+		m.queue = append(m.queue, fired{event: key, payload: payload})
+		m.mu.Unlock()
+		return nil
+	}
+	m.busy = true
+	m.mu.Unlock()
+
+	defer func() {
+		m.mu.Lock()
+		m.busy = false
+		m.queue = nil // only left behind by a panicking guard, action or handler
+		m.mu.Unlock()
+	}()
+
+	var errs []error
+	next := fired{event: key, payload: payload}
+	for {
+		errs = append(errs, m.transition(next.event, next.payload))
+		m.mu.Lock()
+		if len(m.queue) == 0 {
+			m.mu.Unlock()
+			return errors.Join(errs...)
+		}
+		next = m.queue[0]
+		m.queue = m.queue[1:]
+		m.mu.Unlock()
+	}
+}
+
+func (m *Machine) transition(key string, payload any) error {
+	from := m.State()
+	var t *transition
+	for _, c := range m.def.byState[from] {
+		if c.event == key && c.guard(payload) {
+			t = c
+			break
+		}
+	}
+	if t == nil {
+		return &TransitionError{Machine: m.id, State: from, Event: key}
+	}
+	if err := t.action(payload); err != nil {
+		return err
+	}
+	// exit handlers still see the state being left, enter handlers the new one
+	e := StateEvent{Machine: m.id, From: from, To: t.to, Event: key, Payload: payload}
+	exitErr := m.def.Exit(from).Trigger(m.table, e)
+	m.mu.Lock()
+	m.state = t.to
+	m.mu.Unlock()
+	return errors.Join(exitErr, m.def.Enter(t.to).Trigger(m.table, e))
+}
@@ -0,0 +1,171 @@
+package fsm
+
+import (
+	"errors"
+	"github.com/hyicode/utils/assert"
+	"github.com/hyicode/utils/trigger"
+	"strings"
+	"testing"
+)
+
+const (
+	Locked   State = "locked"
+	Unlocked State = "unlocked"
+	Broken   State = "broken"
+
+	Coin trigger.EventName[int]    = "coin"
+	Push trigger.EventName[string] = "push"
+	Kick trigger.EventName[int]    = "kick"
+)
+
+func turnstile(actions *[]string) *Definition {
+	d := NewDefinition("turnstile", Locked)
+	Add(d, Transition[int]{
+		From:  Locked,
+		To:    Unlocked,
+		Event: Coin,
+		Guard: func(cents int) bool { return cents >= 50 },
+		Action: func(cents int) error {
+			*actions = append(*actions, "accept")
+			return nil
+		},
+	})
+	Add(d, Transition[string]{From: Unlocked, To: Locked, Event: Push})
+	Add(d, Transition[int]{
+		From:  Locked,
+		To:    Broken,
+		Event: Kick,
+		Action: func(force int) error {
+			if force < 10 {
+				return errors.New("too weak")
+			}
+			return nil
+		},
+	})
+	return d
+}
+
+func TestMachineFire(t *testing.T) {
+	var actions []string
+	table := trigger.NewEventTable()
+	m := turnstile(&actions).NewMachine("gate1", table)
+
+	var log []string
+	for _, s := range []State{Locked, Unlocked} {
+		m.Definition().Enter(s).On(table, func(e StateEvent) {
+			log = append(log, "enter "+string(e.To)+" on "+e.Event)
+			assert.EqualErrorf(t, e.To, m.State(), "state in enter handler")
+		})
+		m.Definition().Exit(s).On(table, func(e StateEvent) {
+			log = append(log, "exit "+string(e.From))
+			assert.EqualErrorf(t, e.From, m.State(), "state in exit handler")
+		})
+	}
+
+	assert.EqualFatalf(t, Locked, m.State(), "initial state")
+	assert.EqualFatalf(t, true, m.Can("coin"), "can coin")
+	assert.EqualFatalf(t, false, m.Can("push"), "can push")
+
+	err := Fire(m, Coin, 20)
+	var terr *TransitionError
+	assert.EqualFatalf(t, true, errors.As(err, &terr), "guarded coin: %v", err)
+	assert.EqualErrorf(t, true, errors.Is(err, ErrInvalidTransition), "is ErrInvalidTransition")
+	assert.EqualErrorf(t, Locked, terr.State, "error state")
+	assert.EqualErrorf(t, Locked, m.State(), "state after guarded coin")
+
+	assert.EqualFatalf(t, nil, Fire(m, Coin, 50), "coin")
+	assert.EqualFatalf(t, Unlocked, m.State(), "state after coin")
+	assert.EqualFatalf(t, true, errors.Is(Fire(m, Coin, 50), ErrInvalidTransition), "coin twice")
+	assert.EqualFatalf(t, nil, Fire(m, Push, "hard"), "push")
+	assert.EqualFatalf(t, Locked, m.State(), "state after push")
+
+	assert.EqualErrorf(t, "too weak", Fire(m, Kick, 1).Error(), "weak kick")
+	assert.EqualErrorf(t, Locked, m.State(), "state after weak kick")
+
+	assert.EqualErrorf(t, "accept", strings.Join(actions, ","), "actions")
+	assert.EqualErrorf(t, "exit locked,enter unlocked on coin,exit unlocked,enter locked on push",
+		strings.Join(log, ","), "state events")
+}
+
+func TestMachineListen(t *testing.T) {
+	var actions []string
+	table := trigger.NewEventTable()
+	d := turnstile(&actions)
+	m := d.NewMachine("gate1", table)
+	remove := m.Listen()
+
+	// a handler firing while a transition runs is queued until it completes
+	d.Enter(Unlocked).On(table, func(e StateEvent) {
+		Push.Trigger(table, "auto")
+	})
+	var order []string
+	d.Enter(Locked).On(table, func(e StateEvent) {
+		order = append(order, "locked by "+e.Payload.(string))
+	})
+	d.Exit(Unlocked).On(table, func(e StateEvent) {
+		order = append(order, "left unlocked")
+	})
+
+	assert.EqualFatalf(t, nil, Coin.Trigger(table, 100), "trigger coin")
+	assert.EqualErrorf(t, Locked, m.State(), "state")
+	assert.EqualErrorf(t, "left unlocked,locked by auto", strings.Join(order, ","), "order")
+
+	remove()
+	assert.EqualFatalf(t, nil, Kick.Trigger(table, 100), "trigger kick")
+	assert.EqualErrorf(t, Locked, m.State(), "state after remove")
+}
+
+func TestDefinitionDOT(t *testing.T) {
+	var actions []string
+	d := turnstile(&actions)
+	expect := `digraph "turnstile" {
+	rankdir=LR;
+	"" [shape=point];
+	"locked" [shape=box, style="rounded"];
+	"unlocked" [shape=box, style="rounded"];
+	"broken" [shape=box, style="rounded"];
+	"" -> "locked";
+	"locked" -> "unlocked" [label="coin [guarded]"];
+	"unlocked" -> "locked" [label="push"];
+	"locked" -> "broken" [label="kick"];
+}
+`
+	assert.EqualErrorf(t, expect, d.DOT(), "dot")
+	assert.EqualErrorf(t, "locked,unlocked,broken", joinStates(d.States()), "states")
+	assert.EqualErrorf(t, "coin,kick", strings.Join(d.Transitions(Locked), ","), "transitions")
+
+	m := d.NewMachine("gate1", trigger.NewEventTable())
+	assert.EqualErrorf(t, true, strings.Contains(m.DOT(), `"locked" [shape=box, style="rounded,bold"];`), "current state: %s", m.DOT())
+}
+
+func joinStates(states []State) string {
+	var s []string
+	for _, v := range states {
+		s = append(s, string(v))
+	}
+	return strings.Join(s, ",")
+}
+
+func TestMachineFirePanic(t *testing.T) {
+	var actions []string
+	table := trigger.NewEventTable()
+	d := turnstile(&actions)
+	m := d.NewMachine("gate1", table)
+
+	panics := true
+	d.Enter(Unlocked).On(table, func(e StateEvent) {
+		if panics {
+			Fire(m, Push, "queued")
+			panic("boom")
+		}
+	})
+	assert.PanicsWithErrorf(t, "boom", func() { Fire(m, Coin, 50) }, "panicking handler")
+	assert.EqualErrorf(t, Unlocked, m.State(), "queued event dropped")
+
+	// the machine is usable again after the panic
+	panics = false
+	assert.EqualFatalf(t, nil, Fire(m, Push, "hard"), "push")
+	assert.EqualErrorf(t, Locked, m.State(), "state after push")
+	assert.EqualFatalf(t, nil, Fire(m, Coin, 50), "coin")
+	assert.EqualErrorf(t, Unlocked, m.State(), "state after coin")
+}
@@ -0,0 +1,108 @@
+package quick
+
+import (
+	"flag"
+	"fmt"
+	"math/rand/v2"
+	"testing"
+	"time"
+)
+
+var seedFlag = flag.Uint64("quick.seed", 0, "seed of the random inputs of quick.Check, 0 picking one")
+
+// Option changes how Check runs.
+type Option func(c *config)
+
+type config struct {
+	seed       uint64
+	runs       int
+	maxSize    int
+	maxShrinks int
+}
+
+// Seed makes Check generate the same inputs at each run. By default the
+// seed is taken from the -quick.seed flag, or else from the time.
+func Seed(seed uint64) Option {
+	return func(c *config) { c.seed = seed }
+}
+
+// Runs sets how many inputs Check tries, 100 by default.
+func Runs(n int) Option {
+	return func(c *config) { c.runs = n }
+}
+
+// MaxSize sets the size given to the generator by the last run, 100 by
+// default. Sizes grow linearly from 0 over the runs.
+func MaxSize(n int) Option {
+	return func(c *config) { c.maxSize = n }
+}
+
+// MaxShrinks bounds the inputs tried while shrinking, 1000 by default.
+func MaxShrinks(n int) Option {
+	return func(c *config) { c.maxShrinks = n }
+}
+
+// call runs prop, turning a panic into an error.
+func call[T any](prop func(v T) error, v T) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return prop(v)
+}
+
+// Check runs prop on inputs generated by g, and reports an error with the
+// smallest failing input it shrank the first failing one to. A panicking
+// prop fails.
+func Check[T any](t testing.TB, g Gen[T], prop func(v T) error, opts ...Option) {
+	t.Helper()
+	c := config{seed: *seedFlag, runs: 100, maxSize: 100, maxShrinks: 1000}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	if c.seed == 0 {
+		c.seed = uint64(time.Now().UnixNano())
+	}
+	r := rand.New(rand.NewPCG(c.seed, c.seed))
+
+	for run := 0; run < c.runs; run++ {
+		size := c.maxSize
+		if c.runs > 1 {
+			size = run * c.maxSize / (c.runs - 1)
+		}
+		input := g.gen(r, size)
+		err := call(prop, input.value)
+		if err == nil {
+			continue
+		}
+		original := input.value
+		input, err, shrinks := shrink(input, err, prop, c.maxShrinks)
+		t.Errorf("quick: property failed at run %d (-quick.seed=%d)\ninput:%#v\nshrunk %d times to:%#v\nerror:%v",
+			run+1, c.seed, original, shrinks, input.value, err)
+		return
+	}
+}
+
+// shrink looks for the simplest input still failing, trying at most max
+// inputs.
+func shrink[T any](input tree[T], err error, prop func(v T) error, max int) (tree[T], error, int) {
+	tries, shrinks := 0, 0
+	for {
+		shrunk := false
+		for _, c := range input.children() {
+			if tries == max {
+				return input, err, shrinks
+			}
+			tries++
+			if cerr := call(prop, c.value); cerr != nil {
+				input, err, shrunk = c, cerr, true
+				shrinks++
+				break
+			}
+		}
+		if !shrunk {
+			return input, err, shrinks
+		}
+	}
+}
@@ -0,0 +1,341 @@
+// Package quick checks properties against random inputs, shrinking the
+// failing ones to a minimal counterexample:
+//
+//	quick.Check(t, quick.SliceOf(quick.Int(-100, 100)), func(s []int) error {
+//		if sum(s) < 0 {
+//			return fmt.Errorf("negative sum %d", sum(s))
+//		}
+//		return nil
+//	})
+//
+// Shrinking is integrated into generation: a generated value comes with the
+// simpler values it may shrink to, so the values built by Map, Filter or
+// Struct shrink along with the values they are built from.
+package quick
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"reflect"
+	"slices"
+)
+
+// tree is a generated value and the values it shrinks to, simplest first.
+type tree[T any] struct {
+	value   T
+	shrinks func() []tree[T] // nil when the value can't shrink
+}
+
+func (t tree[T]) children() []tree[T] {
+	if t.shrinks == nil {
+		return nil
+	}
+	return t.shrinks()
+}
+
+func mapTree[T, U any](t tree[T], f func(T) U) tree[U] {
+	return tree[U]{value: f(t.value), shrinks: func() []tree[U] {
+		var out []tree[U]
+		for _, c := range t.children() {
+			out = append(out, mapTree(c, f))
+		}
+		return out
+	}}
+}
+
+func filterTree[T any](t tree[T], pred func(T) bool) tree[T] {
+	return tree[T]{value: t.value, shrinks: func() []tree[T] {
+		var out []tree[T]
+		for _, c := range t.children() {
+			if pred(c.value) {
+				out = append(out, filterTree(c, pred))
+			}
+		}
+		return out
+	}}
+}
+
+// Gen generates random values of T. size grows over the runs of Check and
+// bounds the length of the slices, maps and strings generated.
+type Gen[T any] struct {
+	gen func(r *rand.Rand, size int) tree[T]
+}
+
+// New returns a generator calling generate, whose values shrink to the ones
+// returned by shrink, simplest first. shrink may be nil.
+func New[T any](generate func(r *rand.Rand, size int) T, shrink func(v T) []T) Gen[T] {
+	var grow func(v T) tree[T]
+	grow = func(v T) tree[T] {
+		t := tree[T]{value: v}
+		if shrink != nil {
+			t.shrinks = func() []tree[T] {
+				var out []tree[T]
+				for _, s := range shrink(v) {
+					out = append(out, grow(s))
+				}
+				return out
+			}
+		}
+		return t
+	}
+	return Gen[T]{gen: func(r *rand.Rand, size int) tree[T] { return grow(generate(r, size)) }}
+}
+
+// Sample generates a value.
+func (g Gen[T]) Sample(r *rand.Rand, size int) T {
+	return g.gen(r, size).value
+}
+
+// Const always generates v.
+func Const[T any](v T) Gen[T] {
+	return Gen[T]{gen: func(*rand.Rand, int) tree[T] { return tree[T]{value: v} }}
+}
+
+// Elements generates one of values, shrinking towards the first ones.
+func Elements[T any](values ...T) Gen[T] {
+	if len(values) == 0 {
+		panic("quick: Elements needs values")
+	}
+	return Map(Int(0, len(values)-1), func(i int) T { return values[i] })
+}
+
+func intTree(v, target int) tree[int] {
+	return tree[int]{value: v, shrinks: func() []tree[int] {
+		var out []tree[int]
+		for d := v - target; d != 0; d /= 2 {
+			out = append(out, intTree(v-d, target))
+		}
+		return out
+	}}
+}
+
+// Int generates ints in [min, max], shrinking towards the one closest to 0.
+func Int(min, max int) Gen[int] {
+	if min > max {
+		panic(fmt.Sprintf("quick: Int(%d, %d): empty range", min, max))
+	}
+	target := 0
+	switch {
+	case min > 0:
+		target = min
+	case max < 0:
+		target = max
+	}
+	return Gen[int]{gen: func(r *rand.Rand, size int) tree[int] {
+		var v int
+		if span := uint64(max) - uint64(min); span == math.MaxUint64 {
+			v = int(r.Uint64())
+		} else {
+			v = min + int(r.Uint64N(span+1))
+		}
+		return intTree(v, target)
+	}}
+}
+
+// Bool generates booleans, shrinking towards false.
+func Bool() Gen[bool] {
+	return Map(Int(0, 1), func(i int) bool { return i == 1 })
+}
+
+// String generates strings of up to size runes of alphabet, shrinking
+// towards shorter strings of its first runes. An empty alphabet means
+// lowercase letters and digits.
+func String(alphabet string) Gen[string] {
+	if alphabet == "" {
+		alphabet = "abcdefghijklmnopqrstuvwxyz0123456789"
+	}
+	return Map(SliceOf(Elements([]rune(alphabet)...)), func(rs []rune) string { return string(rs) })
+}
+
+func sliceTree[T any](elems []tree[T]) tree[[]T] {
+	value := make([]T, len(elems))
+	for i, e := range elems {
+		value[i] = e.value
+	}
+	return tree[[]T]{value: value, shrinks: func() []tree[[]T] {
+		var out []tree[[]T]
+		// remove chunks, the largest first
+		for k := len(elems); k > 0; k /= 2 {
+			for start := 0; start+k <= len(elems); start += k {
+				out = append(out, sliceTree(slices.Concat(elems[:start], elems[start+k:])))
+			}
+		}
+		for i, e := range elems {
+			for _, c := range e.children() {
+				shrunk := slices.Clone(elems)
+				shrunk[i] = c
+				out = append(out, sliceTree(shrunk))
+			}
+		}
+		return out
+	}}
+}
+
+// SliceOf generates slices of up to size elements of g, shrinking by
+// removing elements and shrinking them.
+func SliceOf[T any](g Gen[T]) Gen[[]T] {
+	return Gen[[]T]{gen: func(r *rand.Rand, size int) tree[[]T] {
+		elems := make([]tree[T], r.IntN(size+1))
+		for i := range elems {
+			elems[i] = g.gen(r, size)
+		}
+		return sliceTree(elems)
+	}}
+}
+
+type entry[K comparable, V any] struct {
+	key   K
+	value tree[V]
+}
+
+func mapOfTree[K comparable, V any](entries []entry[K, V]) tree[map[K]V] {
+	value := make(map[K]V, len(entries))
+	for _, e := range entries {
+		value[e.key] = e.value.value
+	}
+	return tree[map[K]V]{value: value, shrinks: func() []tree[map[K]V] {
+		var out []tree[map[K]V]
+		for i := range entries {
+			out = append(out, mapOfTree(slices.Concat(entries[:i], entries[i+1:])))
+		}
+		for i, e := range entries {
+			for _, c := range e.value.children() {
+				shrunk := slices.Clone(entries)
+				shrunk[i].value = c
+				out = append(out, mapOfTree(shrunk))
+			}
+		}
+		return out
+	}}
+}
+
+// MapOf generates maps of up to size entries, shrinking by removing entries
+// and shrinking their values.
+func MapOf[K comparable, V any](keys Gen[K], values Gen[V]) Gen[map[K]V] {
+	return Gen[map[K]V]{gen: func(r *rand.Rand, size int) tree[map[K]V] {
+		n := r.IntN(size + 1)
+		seen := make(map[K]bool)
+		var entries []entry[K, V]
+		// give up on keys after as many collisions as wanted entries
+		for tries := 0; len(entries) < n && tries < 2*n; tries++ {
+			k := keys.Sample(r, size)
+			if seen[k] {
+				continue
+			}
+			seen[k] = true
+			entries = append(entries, entry[K, V]{key: k, value: values.gen(r, size)})
+		}
+		return mapOfTree(entries)
+	}}
+}
+
+// anyGen is a Gen of any type, as Struct takes them.
+type anyGen interface {
+	anyTree(r *rand.Rand, size int) tree[any]
+	valueType() reflect.Type
+}
+
+func (g Gen[T]) anyTree(r *rand.Rand, size int) tree[any] {
+	return mapTree(g.gen(r, size), func(v T) any { return v })
+}
+
+func (g Gen[T]) valueType() reflect.Type { return reflect.TypeFor[T]() }
+
+type fieldTree struct {
+	index int
+	tree  tree[any]
+}
+
+func structTree[T any](fields []fieldTree) tree[T] {
+	var value T
+	rv := reflect.ValueOf(&value).Elem()
+	for _, f := range fields {
+		if f.tree.value != nil { // a nil interface leaves the zero value
+			rv.Field(f.index).Set(reflect.ValueOf(f.tree.value))
+		}
+	}
+	return tree[T]{value: value, shrinks: func() []tree[T] {
+		var out []tree[T]
+		for i, f := range fields {
+			for _, c := range f.tree.children() {
+				shrunk := slices.Clone(fields)
+				shrunk[i].tree = c
+				out = append(out, structTree[T](shrunk))
+			}
+		}
+		return out
+	}}
+}
+
+// Struct generates structs of type T whose exported fields are generated by
+// the Gen of fields of the same name, the other fields keeping their zero
+// value. It panics if a field is missing or has another type than its Gen.
+//
+//	quick.Struct[User](map[string]any{"Name": quick.String(""), "Age": quick.Int(0, 120)})
+func Struct[T any](fields map[string]any) Gen[T] {
+	typ := reflect.TypeFor[T]()
+	if typ.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("quick: Struct: %v is not a struct", typ))
+	}
+	type fieldGen struct {
+		index int
+		gen   anyGen
+	}
+	var gens []fieldGen
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		g, ok := fields[f.Name]
+		if !ok {
+			continue
+		}
+		ag, ok := g.(anyGen)
+		if !ok || ag.valueType() != f.Type || !f.IsExported() {
+			panic(fmt.Sprintf("quick: Struct: %T can't generate the field %s %v of %v", g, f.Name, f.Type, typ))
+		}
+		gens = append(gens, fieldGen{index: i, gen: ag})
+	}
+	if len(gens) != len(fields) {
+		panic(fmt.Sprintf("quick: Struct: %v misses some of the fields given", typ))
+	}
+	return Gen[T]{gen: func(r *rand.Rand, size int) tree[T] {
+		trees := make([]fieldTree, len(gens))
+		for i, g := range gens {
+			trees[i] = fieldTree{index: g.index, tree: g.gen.anyTree(r, size)}
+		}
+		return structTree[T](trees)
+	}}
+}
+
+// OneOf generates values with one of gens, picked at random.
+func OneOf[T any](gens ...Gen[T]) Gen[T] {
+	if len(gens) == 0 {
+		panic("quick: OneOf needs generators")
+	}
+	return Gen[T]{gen: func(r *rand.Rand, size int) tree[T] {
+		return gens[r.IntN(len(gens))].gen(r, size)
+	}}
+}
+
+// Map generates f(v) for the values v of g.
+func Map[T, U any](g Gen[T], f func(T) U) Gen[U] {
+	return Gen[U]{gen: func(r *rand.Rand, size int) tree[U] {
+		return mapTree(g.gen(r, size), f)
+	}}
+}
+
+// maxFilterTries bounds the values Filter generates to find an accepted one.
+const maxFilterTries = 1000
+
+// Filter generates the values of g accepted by pred. It panics when pred
+// rejects too many values in a row.
+func Filter[T any](g Gen[T], pred func(T) bool) Gen[T] {
+	return Gen[T]{gen: func(r *rand.Rand, size int) tree[T] {
+		for i := 0; i < maxFilterTries; i++ {
+			if t := g.gen(r, size); pred(t.value) {
+				return filterTree(t, pred)
+			}
+		}
+		panic(fmt.Sprintf("quick: Filter rejected %d values in a row", maxFilterTries))
+	}}
+}
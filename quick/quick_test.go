@@ -0,0 +1,145 @@
+package quick
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hyicode/utils/assert"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// fakeTB records the errors reported to it instead of failing the test.
+type fakeTB struct {
+	testing.TB
+	errors []string
+}
+
+func (tb *fakeTB) Helper() {}
+
+func (tb *fakeTB) Errorf(format string, args ...any) {
+	tb.errors = append(tb.errors, fmt.Sprintf(format, args...))
+}
+
+// counterexample returns the shrunk input Check reports for prop.
+func counterexample[T any](t *testing.T, g Gen[T], prop func(v T) error, opts ...Option) string {
+	t.Helper()
+	tb := &fakeTB{TB: t}
+	Check(tb, g, prop, append([]Option{Seed(1)}, opts...)...)
+	assert.LenFatalf(t, tb.errors, 1, "errors: %q", tb.errors)
+	_, shrunk, _ := strings.Cut(tb.errors[0], "to:")
+	shrunk, _, _ = strings.Cut(shrunk, "\n")
+	return shrunk
+}
+
+func TestCheckShrinks(t *testing.T) {
+	assert.EqualErrorf(t, "11", counterexample(t, Int(-1000, 1000), func(v int) error {
+		if v > 10 {
+			return errors.New("too large")
+		}
+		return nil
+	}), "int")
+	assert.EqualErrorf(t, "-5", counterexample(t, Int(-1000, 1000), func(v int) error {
+		if v <= -5 {
+			return errors.New("too small")
+		}
+		return nil
+	}), "negative int")
+	assert.EqualErrorf(t, "[]int{5}", counterexample(t, SliceOf(Int(0, 100)), func(s []int) error {
+		if slices.ContainsFunc(s, func(v int) bool { return v >= 5 }) {
+			return errors.New("large element")
+		}
+		return nil
+	}), "slice")
+	assert.EqualErrorf(t, `"b"`, counterexample(t, String("ab"), func(s string) error {
+		if strings.Contains(s, "b") {
+			return errors.New("has b")
+		}
+		return nil
+	}), "string")
+	// keys don't shrink
+	assert.EqualErrorf(t, `map[string]int{"k":3}`, counterexample(t, MapOf(Const("k"), Int(0, 10)), func(m map[string]int) error {
+		if m["k"] >= 3 {
+			return errors.New("large value")
+		}
+		return nil
+	}), "map")
+	assert.EqualErrorf(t, "10", counterexample(t, Int(0, 1000), func(v int) error {
+		if v >= 10 {
+			panic("too large")
+		}
+		return nil
+	}), "panic")
+}
+
+type point struct {
+	X, Y int
+	Tag  string
+	note string
+}
+
+func TestCombinators(t *testing.T) {
+	not11 := Filter(Int(0, 1000), func(v int) bool { return v != 11 })
+	assert.EqualErrorf(t, "12", counterexample(t, not11, func(v int) error {
+		if v > 10 {
+			return errors.New("too large")
+		}
+		return nil
+	}), "filter")
+
+	square := Map(Int(0, 100), func(v int) int { return v * v })
+	assert.EqualErrorf(t, "64", counterexample(t, square, func(v int) error {
+		if v > 50 {
+			return errors.New("too large")
+		}
+		return nil
+	}), "map")
+
+	points := Struct[point](map[string]any{"X": Int(-100, 100), "Y": Int(-100, 100), "Tag": Elements("a", "b")})
+	assert.EqualErrorf(t, `quick.point{X:0, Y:3, Tag:"a", note:""}`, counterexample(t, points, func(p point) error {
+		if p.Y > 2 {
+			return errors.New("too high")
+		}
+		return nil
+	}), "struct")
+
+	r := rand.New(rand.NewPCG(1, 1))
+	seen := make(map[int]bool)
+	g := OneOf(Const(1), Const(2), Elements(3))
+	for i := 0; i < 100; i++ {
+		seen[g.Sample(r, 10)] = true
+	}
+	assert.DeepEqualErrorf(t, map[int]bool{1: true, 2: true, 3: true}, seen, "one of")
+
+	assert.PanicsErrorf(t, func() { Struct[point](map[string]any{"X": String("")}) }, "field type")
+	assert.PanicsErrorf(t, func() { Struct[point](map[string]any{"Z": Int(0, 1)}) }, "missing field")
+	assert.PanicsErrorf(t, func() { Struct[point](map[string]any{"note": String("")}) }, "unexported field")
+}
+
+func TestCheck(t *testing.T) {
+	var sizes []int
+	Check(t, New(func(r *rand.Rand, size int) int { return size }, nil), func(size int) error {
+		sizes = append(sizes, size)
+		return nil
+	}, Runs(5), MaxSize(20))
+	assert.DeepEqualErrorf(t, []int{0, 5, 10, 15, 20}, sizes, "sizes")
+
+	sample := func(seed uint64) []int {
+		var got []int
+		Check(t, Int(0, 1<<30), func(v int) error {
+			got = append(got, v)
+			return nil
+		}, Seed(seed), Runs(10))
+		return got
+	}
+	assert.DeepEqualErrorf(t, sample(7), sample(7), "same seed")
+
+	tb := &fakeTB{TB: t}
+	Check(tb, New(func(*rand.Rand, int) int { return 100 }, func(v int) []int { return []int{v - 1} }), func(v int) error {
+		return fmt.Errorf("fails with %d", v)
+	}, Seed(3), MaxShrinks(10))
+	assert.LenFatalf(t, tb.errors, 1, "errors: %q", tb.errors)
+	assert.EqualErrorf(t, "quick: property failed at run 1 (-quick.seed=3)\ninput:100\nshrunk 10 times to:90\nerror:fails with 90",
+		tb.errors[0], "report")
+}
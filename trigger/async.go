@@ -0,0 +1,220 @@
+package trigger
+
+import (
+	"context"
+	"errors"
+	"hash/maphash"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+var ErrTableClosed = errors.New("trigger: event table closed")
+
+// OverflowPolicy decides what happens to an event that arrives at a full queue.
+type OverflowPolicy int
+
+const (
+	// Block waits until the queue has room.
+	Block OverflowPolicy = iota
+	// DropOldest discards the oldest queued event to make room.
+	DropOldest
+	// DropNewest discards the event being triggered.
+	DropNewest
+	// CoalesceLatest keeps only the newest event, replacing the one pending.
+	// Subscriptions ignore their buffer size with it. AsyncEventTable
+	// doesn't support it, since a worker queue is shared between keys.
+	CoalesceLatest
+)
+
+type AsyncConfig struct {
+	Workers   int // defaults to runtime.GOMAXPROCS(0)
+	QueueSize int // per worker, defaults to 1024
+	Overflow  OverflowPolicy
+}
+
+type asyncEvent struct {
+	key   string
+	event any
+}
+
+// AsyncEventTable runs handlers on a fixed pool of workers. Every key is
+// pinned to one worker, so events triggered for the same key from one
+// goroutine are handled in order.
+//
+// With the Block policy a handler must not trigger an event on the table it
+// is running on, since its own worker may be the one it waits for.
+type AsyncEventTable struct {
+	table   EventTableI
+	policy  OverflowPolicy
+	seed    maphash.Seed
+	queues  []chan asyncEvent
+	workers sync.WaitGroup
+	dropped atomic.Uint64
+
+	mu        sync.RWMutex // held for reading while enqueuing
+	closed    bool
+	done      chan struct{}
+	closeOnce sync.Once
+
+	pendingMu sync.Mutex
+	pending   int
+	idle      chan struct{}
+}
+
+// NewAsyncEventTable dispatches the events of table asynchronously. table is
+// read by several workers at once, so it must be safe for concurrent use; a
+// nil table is replaced by NewEventTableMutex().
+func NewAsyncEventTable(table EventTableI, cfg AsyncConfig) *AsyncEventTable {
+	if table == nil {
+		table = NewEventTableMutex()
+	}
+	if cfg.Workers <= 0 {
+		cfg.Workers = runtime.GOMAXPROCS(0)
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = 1024
+	}
+	if cfg.Overflow == CoalesceLatest {
+		panic("trigger: AsyncEventTable doesn't support CoalesceLatest")
+	}
+	t := &AsyncEventTable{
+		table:  table,
+		policy: cfg.Overflow,
+		seed:   maphash.MakeSeed(),
+		queues: make([]chan asyncEvent, cfg.Workers),
+		done:   make(chan struct{}),
+	}
+	t.workers.Add(cfg.Workers)
+	for i := range t.queues {
+		t.queues[i] = make(chan asyncEvent, cfg.QueueSize)
+		go t.work(t.queues[i])
+	}
+	return t
+}
+
+func (t *AsyncEventTable) RegisterCB(key string, cb CB) {
+	t.table.RegisterCB(key, cb)
+}
+
+func (t *AsyncEventTable) RegisterRemovableCB(key string, cb CB) (remove func()) {
+	return RegisterRemovable(t.table, key, cb)
+}
+
+func (t *AsyncEventTable) CBList(key string) []CB {
+	return t.table.CBList(key)
+}
+
+// Dispatch queues event for the worker owning key. It returns ErrTableClosed
+// once Close has been called.
+func (t *AsyncEventTable) Dispatch(key string, event any) error {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.closed {
+		return ErrTableClosed
+	}
+
+	q := t.queues[maphash.String(t.seed, key)%uint64(len(t.queues))]
+	ev := asyncEvent{key: key, event: event}
+	t.begin()
+	switch t.policy {
+	case DropNewest:
+		select {
+		case q <- ev:
+		default:
+			t.drop()
+		}
+	case DropOldest:
+		for {
+			select {
+			case q <- ev:
+				return nil
+			default:
+			}
+			select {
+			case <-q:
+				t.drop()
+			default:
+			}
+		}
+	default:
+		select {
+		case q <- ev:
+		case <-t.done:
+			t.end()
+			return ErrTableClosed
+		}
+	}
+	return nil
+}
+
+// Dropped returns the number of events discarded by the overflow policy.
+func (t *AsyncEventTable) Dropped() uint64 {
+	return t.dropped.Load()
+}
+
+// Flush waits until every queued event, including the ones triggered by
+// handlers in the meantime, has been handled.
+func (t *AsyncEventTable) Flush(ctx context.Context) error {
+	t.pendingMu.Lock()
+	if t.pending == 0 {
+		t.pendingMu.Unlock()
+		return nil
+	}
+	idle := t.idle
+	t.pendingMu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops accepting events, handles the ones already queued and waits
+// for the workers to exit. It must not be called from a handler.
+func (t *AsyncEventTable) Close() error {
+	t.closeOnce.Do(func() {
+		close(t.done)
+		t.mu.Lock()
+		t.closed = true
+		t.mu.Unlock()
+		for _, q := range t.queues {
+			close(q)
+		}
+	})
+	t.workers.Wait()
+	return nil
+}
+
+func (t *AsyncEventTable) work(q chan asyncEvent) {
+	defer t.workers.Done()
+	for ev := range q {
+		_ = Dispatch(t.table, ev.key, ev.event)
+		t.end()
+	}
+}
+
+func (t *AsyncEventTable) drop() {
+	t.dropped.Add(1)
+	t.end()
+}
+
+func (t *AsyncEventTable) begin() {
+	t.pendingMu.Lock()
+	defer t.pendingMu.Unlock()
+	if t.pending == 0 {
+		t.idle = make(chan struct{})
+	}
+	t.pending++
+}
+
+func (t *AsyncEventTable) end() {
+	t.pendingMu.Lock()
+	defer t.pendingMu.Unlock()
+	t.pending--
+	if t.pending == 0 {
+		close(t.idle)
+	}
+}
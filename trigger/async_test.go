@@ -0,0 +1,108 @@
+package trigger
+
+import (
+	"context"
+	"github.com/hyicode/utils/assert"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestAsyncEventTableOrder(t *testing.T) {
+	const (
+		KeyNum   = 10
+		EventNum = 1000
+	)
+	table := NewAsyncEventTable(nil, AsyncConfig{Workers: 4, QueueSize: 16})
+	defer table.Close()
+
+	var mu sync.Mutex
+	got := make(map[string][]int)
+	keys := make([]EventName[int], 0, KeyNum)
+	for i := 0; i < KeyNum; i++ {
+		key := EventName[int]("async_" + strconv.Itoa(i))
+		key.On(table, func(event int) {
+			mu.Lock()
+			got[string(key)] = append(got[string(key)], event)
+			mu.Unlock()
+		})
+		keys = append(keys, key)
+	}
+
+	for i := 0; i < EventNum; i++ {
+		for _, key := range keys {
+			assert.EqualFatalf(t, nil, key.Trigger(table, i), "trigger")
+		}
+	}
+	assert.EqualFatalf(t, nil, table.Flush(context.Background()), "flush")
+
+	for _, key := range keys {
+		events := got[string(key)]
+		assert.EqualFatalf(t, EventNum, len(events), "%s len", key)
+		for i, v := range events {
+			assert.EqualFatalf(t, i, v, "%s order", key)
+		}
+	}
+}
+
+func TestAsyncEventTableOverflow(t *testing.T) {
+	tests := []struct {
+		policy  OverflowPolicy
+		handled []int
+	}{
+		{policy: DropNewest, handled: []int{0, 1, 2}},
+		{policy: DropOldest, handled: []int{0, 4, 5}},
+	}
+	for _, tt := range tests {
+		const key EventName[int] = "overflow"
+		table := NewAsyncEventTable(nil, AsyncConfig{Workers: 1, QueueSize: 2, Overflow: tt.policy})
+
+		started := make(chan struct{})
+		release := make(chan struct{})
+		var handled []int
+		key.On(table, func(event int) {
+			if event == 0 {
+				close(started)
+				<-release
+			}
+			handled = append(handled, event)
+		})
+
+		key.Trigger(table, 0)
+		<-started
+		for i := 1; i <= 5; i++ {
+			key.Trigger(table, i)
+		}
+		close(release)
+		assert.EqualFatalf(t, nil, table.Flush(context.Background()), "flush")
+		assert.EqualFatalf(t, uint64(3), table.Dropped(), "dropped")
+		assert.EqualFatalf(t, len(tt.handled), len(handled), "handled len")
+		for i := range handled {
+			assert.EqualErrorf(t, tt.handled[i], handled[i], "handled")
+		}
+		table.Close()
+	}
+}
+
+func TestAsyncEventTableCoalesceLatest(t *testing.T) {
+	assert.PanicsErrorf(t, func() {
+		NewAsyncEventTable(nil, AsyncConfig{Overflow: CoalesceLatest})
+	}, "unsupported policy")
+}
+
+func TestAsyncEventTableClose(t *testing.T) {
+	const key EventName[string] = "close"
+	assert.NoGoroutineLeaks(t)
+	table := NewAsyncEventTable(nil, AsyncConfig{Workers: 2})
+	count := 0
+	key.On(table, func(event string) {
+		count++
+	})
+	for i := 0; i < 100; i++ {
+		key.Trigger(table, "x")
+	}
+	table.Close()
+	assert.EqualFatalf(t, 100, count, "handled before close")
+	assert.EqualFatalf(t, ErrTableClosed, key.Trigger(table, "x"), "trigger after close")
+	assert.EqualFatalf(t, nil, table.Flush(context.Background()), "flush after close")
+}
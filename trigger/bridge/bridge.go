@@ -0,0 +1,277 @@
+// Package bridge forwards events between the trigger tables of processes on
+// the same host, over Unix domain sockets or pipes.
+//
+// Both ends of a connection are peers running ServeConn: each one sends the
+// keys it subscribes to, and forwards the events of the keys the other one
+// subscribed to as they are triggered on its table. Received events are
+// decoded with a trigger.Registry and dispatched on the local table.
+//
+// A key should flow one way: an event received from a peer that also
+// subscribed to its key is sent back to it.
+package bridge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/hyicode/utils/clock"
+	"github.com/hyicode/utils/trigger"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+var ErrDropped = errors.New("bridge: send queue full, event dropped")
+
+type Config struct {
+	// Table is where received events are triggered and local events are
+	// forwarded from. Handlers are registered on it from the connection's
+	// goroutines, so it must be safe for concurrent use, like
+	// trigger.NewEventTableMutex().
+	Table trigger.EventTableI
+	// Subscribe lists the keys to receive from the remote side, Registry
+	// decodes their payloads.
+	Subscribe []string
+	Registry  *trigger.Registry
+	// Export lists the keys the remote side may subscribe to, nil allowing
+	// any.
+	Export    []string
+	QueueSize int // events waiting to be sent, defaults to 1024
+	// OnSubscribe is called with the keys forwarded to the remote side each
+	// time it subscribes.
+	OnSubscribe func(keys []string)
+	// OnError is called with the errors that can't be returned, possibly
+	// from the goroutines triggering events.
+	OnError func(err error)
+
+	Backoff    time.Duration // first delay before Connect redials, defaults to 100ms
+	MaxBackoff time.Duration // defaults to 5s
+	Clock      clock.Clock   // nil meaning clock.Real
+}
+
+func (cfg Config) withDefaults() Config {
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = 1024
+	}
+	if cfg.Backoff <= 0 {
+		cfg.Backoff = 100 * time.Millisecond
+	}
+	if cfg.MaxBackoff < cfg.Backoff {
+		cfg.MaxBackoff = max(5*time.Second, cfg.Backoff)
+	}
+	if cfg.Clock == nil {
+		cfg.Clock = clock.Real
+	}
+	return cfg
+}
+
+func (cfg Config) report(err error) {
+	if err != nil && cfg.OnError != nil {
+		cfg.OnError(err)
+	}
+}
+
+type peer struct {
+	cfg    Config
+	conn   io.ReadWriteCloser
+	out    chan frame
+	done   chan struct{}
+	wanted map[string]bool
+	export map[string]bool // nil allowing any key
+
+	removes []func() // forwarders of the remote subscription, read loop only
+}
+
+// ServeConn runs a peer on conn until either side closes it or ctx is done.
+// It returns nil when the remote side closed the connection.
+func ServeConn(ctx context.Context, conn io.ReadWriteCloser, cfg Config) error {
+	cfg = cfg.withDefaults()
+	p := &peer{
+		cfg:    cfg,
+		conn:   conn,
+		out:    make(chan frame, cfg.QueueSize),
+		done:   make(chan struct{}),
+		wanted: make(map[string]bool),
+	}
+	for _, key := range cfg.Subscribe {
+		p.wanted[key] = true
+	}
+	if cfg.Export != nil {
+		p.export = make(map[string]bool)
+		for _, key := range cfg.Export {
+			p.export[key] = true
+		}
+	}
+
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+	p.out <- frame{T: frameSub, Keys: cfg.Subscribe}
+	writeErr := make(chan error, 1)
+	go func() { writeErr <- p.writeLoop() }()
+
+	err := p.readLoop()
+	close(p.done)
+	p.unsubscribe()
+	conn.Close()
+	if werr := <-writeErr; werr != nil {
+		err = werr
+	}
+	switch {
+	case ctx.Err() != nil:
+		return ctx.Err()
+	case errors.Is(err, io.EOF):
+		return nil
+	}
+	return err
+}
+
+func (p *peer) writeLoop() error {
+	for {
+		select {
+		case f := <-p.out:
+			err := writeFrame(p.conn, f)
+			if errors.Is(err, ErrFrameTooLarge) {
+				p.cfg.report(err)
+				continue
+			}
+			if err != nil {
+				p.conn.Close()
+				return err
+			}
+		case <-p.done:
+			return nil
+		}
+	}
+}
+
+func (p *peer) readLoop() error {
+	for {
+		f, err := readFrame(p.conn)
+		if err != nil {
+			return err
+		}
+		switch f.T {
+		case frameSub:
+			p.subscribe(f.Keys)
+		case frameEvent:
+			p.receive(f.Key, f.Payload)
+		default:
+			p.cfg.report(fmt.Errorf("bridge: unknown frame type %q", f.T))
+		}
+	}
+}
+
+func (p *peer) subscribe(keys []string) {
+	p.unsubscribe()
+	accepted := []string{}
+	seen := make(map[string]bool)
+	for _, key := range keys {
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		if p.export != nil && !p.export[key] {
+			p.cfg.report(fmt.Errorf("bridge: remote subscribed to unexported event %q", key))
+			continue
+		}
+		p.removes = append(p.removes, trigger.RegisterRemovable(p.cfg.Table, key, p.forwarder(key)))
+		accepted = append(accepted, key)
+	}
+	if p.cfg.OnSubscribe != nil {
+		p.cfg.OnSubscribe(accepted)
+	}
+}
+
+func (p *peer) unsubscribe() {
+	for _, remove := range p.removes {
+		remove()
+	}
+	p.removes = nil
+}
+
+func (p *peer) forwarder(key string) trigger.CB {
+	return func(event any) {
+		data, err := json.Marshal(event)
+		if err != nil {
+			p.cfg.report(fmt.Errorf("bridge: encode %q: %w", key, err))
+			return
+		}
+		select {
+		case p.out <- frame{T: frameEvent, Key: key, Payload: data}:
+		case <-p.done:
+		default:
+			p.cfg.report(fmt.Errorf("%w: %q", ErrDropped, key))
+		}
+	}
+}
+
+func (p *peer) receive(key string, data json.RawMessage) {
+	if !p.wanted[key] {
+		p.cfg.report(fmt.Errorf("bridge: received unsubscribed event %q", key))
+		return
+	}
+	if p.cfg.Registry == nil {
+		p.cfg.report(fmt.Errorf("bridge: no registry to decode %q", key))
+		return
+	}
+	event, err := p.cfg.Registry.Decode(key, data)
+	if err != nil {
+		p.cfg.report(err)
+		return
+	}
+	p.cfg.report(trigger.Dispatch(p.cfg.Table, key, event))
+}
+
+// Serve runs a peer on each connection accepted on ln until ctx is done or
+// accepting fails. It closes ln and waits for the connections to end.
+func Serve(ctx context.Context, ln net.Listener, cfg Config) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stop := context.AfterFunc(ctx, func() { ln.Close() })
+	defer stop()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := ServeConn(ctx, conn, cfg); err != nil && ctx.Err() == nil {
+				cfg.report(err)
+			}
+		}()
+	}
+}
+
+// Connect dials address and runs a peer on the connection, dialing again
+// with an exponential backoff whenever that fails or the connection ends,
+// until ctx is done.
+func Connect(ctx context.Context, network, address string, cfg Config) error {
+	cfg = cfg.withDefaults()
+	var dialer net.Dialer
+	backoff := cfg.Backoff
+	for {
+		conn, err := dialer.DialContext(ctx, network, address)
+		if err == nil {
+			backoff = cfg.Backoff
+			err = ServeConn(ctx, conn, cfg)
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		cfg.report(err)
+		if err := cfg.Clock.SleepContext(ctx, backoff); err != nil {
+			return err
+		}
+		backoff = min(2*backoff, cfg.MaxBackoff)
+	}
+}
@@ -0,0 +1,180 @@
+package bridge
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"github.com/hyicode/utils/assert"
+	"github.com/hyicode/utils/trigger"
+	"io"
+	"net"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type score struct {
+	Player string
+	Points int
+}
+
+const (
+	Score  trigger.EventName[score]  = "score"
+	Secret trigger.EventName[string] = "secret"
+)
+
+func receive[T any](t *testing.T, ch <-chan T, what string) T {
+	t.Helper()
+	select {
+	case v := <-ch:
+		return v
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for %s", what)
+		panic("unreachable")
+	}
+}
+
+type errorLog struct {
+	mu   sync.Mutex
+	errs []error
+}
+
+func (l *errorLog) add(err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.errs = append(l.errs, err)
+}
+
+func (l *errorLog) String() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var s []string
+	for _, err := range l.errs {
+		s = append(s, err.Error())
+	}
+	return strings.Join(s, "\n")
+}
+
+func TestServeConnPipe(t *testing.T) {
+	server, client := net.Pipe()
+	serverTable, clientTable := trigger.NewEventTableMutex(), trigger.NewEventTableMutex()
+	registry := trigger.NewRegistry()
+	Score.Register(registry)
+
+	subscribed := make(chan []string, 1)
+	var serverErrs errorLog
+	ctx, cancel := context.WithCancel(context.Background())
+	serverDone := make(chan error, 1)
+	go func() {
+		serverDone <- ServeConn(ctx, server, Config{
+			Table:       serverTable,
+			Export:      []string{"score"},
+			OnSubscribe: func(keys []string) { subscribed <- keys },
+			OnError:     serverErrs.add,
+		})
+	}()
+	clientDone := make(chan error, 1)
+	go func() {
+		clientDone <- ServeConn(context.Background(), client, Config{
+			Table:     clientTable,
+			Subscribe: []string{"score", "secret"},
+			Registry:  registry,
+		})
+	}()
+
+	received := make(chan score, 1)
+	Score.On(clientTable, func(event score) { received <- event })
+
+	keys := receive(t, subscribed, "subscription")
+	assert.EqualErrorf(t, "score", strings.Join(keys, ","), "exported subscription")
+	assert.EqualErrorf(t, `bridge: remote subscribed to unexported event "secret"`, serverErrs.String(), "errors")
+
+	assert.EqualFatalf(t, nil, Secret.Trigger(serverTable, "hidden"), "trigger secret")
+	assert.EqualFatalf(t, nil, Score.Trigger(serverTable, score{"ann", 3}), "trigger score")
+	assert.EqualErrorf(t, score{"ann", 3}, receive(t, received, "score"), "received")
+
+	cancel()
+	assert.EqualErrorf(t, context.Canceled, receive(t, serverDone, "server end"), "server end")
+	assert.EqualErrorf(t, nil, receive(t, clientDone, "client end"), "client end")
+	assert.EqualErrorf(t, 0, len(serverTable.CBList("score")), "forwarders left")
+}
+
+func TestServeConnect(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bridge.sock")
+	serverTable, clientTable := trigger.NewEventTableMutex(), trigger.NewEventTableMutex()
+	registry := trigger.NewRegistry()
+	Score.Register(registry)
+	subscribed := make(chan []string, 1)
+
+	serve := func() (stop func()) {
+		ln, err := net.Listen("unix", path)
+		assert.EqualFatalf(t, nil, err, "listen")
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() {
+			done <- Serve(ctx, ln, Config{
+				Table:       serverTable,
+				OnSubscribe: func(keys []string) { subscribed <- keys },
+			})
+		}()
+		return func() {
+			cancel()
+			assert.EqualErrorf(t, context.Canceled, receive(t, done, "serve end"), "serve end")
+		}
+	}
+
+	received := make(chan score, 1)
+	Score.On(clientTable, func(event score) { received <- event })
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	clientDone := make(chan error, 1)
+	go func() {
+		clientDone <- Connect(ctx, "unix", path, Config{
+			Table:      clientTable,
+			Subscribe:  []string{"score"},
+			Registry:   registry,
+			Backoff:    5 * time.Millisecond,
+			MaxBackoff: 20 * time.Millisecond,
+		})
+	}()
+
+	// the client retries until the server is up
+	time.Sleep(30 * time.Millisecond)
+	stop := serve()
+	receive(t, subscribed, "first subscription")
+	assert.EqualFatalf(t, nil, Score.Trigger(serverTable, score{"bob", 1}), "trigger")
+	assert.EqualErrorf(t, score{"bob", 1}, receive(t, received, "first score"), "first score")
+
+	stop()
+	stop = serve()
+	receive(t, subscribed, "subscription after reconnect")
+	assert.EqualFatalf(t, nil, Score.Trigger(serverTable, score{"bob", 2}), "trigger")
+	assert.EqualErrorf(t, score{"bob", 2}, receive(t, received, "score after reconnect"), "score after reconnect")
+
+	cancel()
+	assert.EqualErrorf(t, context.Canceled, receive(t, clientDone, "client end"), "client end")
+	stop()
+}
+
+func TestReadFrame(t *testing.T) {
+	var buf bytes.Buffer
+	assert.EqualFatalf(t, nil, writeFrame(&buf, frame{T: frameEvent, Key: "k", Payload: []byte(`{"a":1}`)}), "write")
+	f, err := readFrame(&buf)
+	assert.EqualFatalf(t, nil, err, "read")
+	assert.EqualErrorf(t, `ev k {"a":1}`, f.T+" "+f.Key+" "+string(f.Payload), "frame")
+
+	_, err = readFrame(&buf)
+	assert.EqualErrorf(t, true, errors.Is(err, io.EOF), "read at end: %v", err)
+
+	big := frame{T: frameEvent, Key: "big", Payload: []byte(`"` + strings.Repeat("x", MaxFrameSize) + `"`)}
+	err = writeFrame(&buf, big)
+	assert.EqualErrorf(t, true, errors.Is(err, ErrFrameTooLarge), "write oversized: %v", err)
+	assert.EqualErrorf(t, 0, buf.Len(), "nothing written")
+
+	binary.Write(&buf, binary.BigEndian, uint32(MaxFrameSize+1))
+	_, err = readFrame(&buf)
+	assert.EqualErrorf(t, true, err != nil && strings.Contains(err.Error(), "MaxFrameSize"), "oversized: %v", err)
+}
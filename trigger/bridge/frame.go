@@ -0,0 +1,65 @@
+package bridge
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// MaxFrameSize bounds the frames exchanged with a peer.
+const MaxFrameSize = 16 << 20
+
+// ErrFrameTooLarge is reported for the events whose frame exceeds
+// MaxFrameSize, which the peer would refuse.
+var ErrFrameTooLarge = errors.New("bridge: frame exceeds MaxFrameSize")
+
+const (
+	frameSub   = "sub" // the keys the sender wants to receive
+	frameEvent = "ev"  // an event of a key the receiver subscribed to
+)
+
+// frame is sent as a 4-byte big-endian length followed by as many bytes of
+// JSON.
+type frame struct {
+	T       string          `json:"t"`
+	Keys    []string        `json:"keys,omitempty"`
+	Key     string          `json:"key,omitempty"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+func writeFrame(w io.Writer, f frame) error {
+	data, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+	if len(data) > MaxFrameSize {
+		return fmt.Errorf("%w: %q of %d bytes", ErrFrameTooLarge, f.Key, len(data))
+	}
+	buf := make([]byte, 4+len(data))
+	binary.BigEndian.PutUint32(buf, uint32(len(data)))
+	copy(buf[4:], data)
+	_, err = w.Write(buf)
+	return err
+}
+
+func readFrame(r io.Reader) (frame, error) {
+	var f frame
+	var size [4]byte
+	if _, err := io.ReadFull(r, size[:]); err != nil {
+		return f, err
+	}
+	n := binary.BigEndian.Uint32(size[:])
+	if n > MaxFrameSize {
+		return f, fmt.Errorf("bridge: frame of %d bytes exceeds MaxFrameSize", n)
+	}
+	data := make([]byte, n)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return f, err
+	}
+	if err := json.Unmarshal(data, &f); err != nil {
+		return f, fmt.Errorf("bridge: bad frame: %w", err)
+	}
+	return f, nil
+}
@@ -0,0 +1,108 @@
+package trigger
+
+import (
+	"hash/maphash"
+	"maps"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// cowShards splits the key space, so registering a new key only copies the
+// snapshot of one shard instead of the whole table.
+const cowShards = 64
+
+type cowList struct {
+	cbs atomic.Pointer[[]CB]
+	ids []uint64 // parallel to cbs once a removable CB was registered, guarded by EventTableCOW.mu
+}
+
+// EventTableCOW is a concurrency-safe table for read-heavy workloads. The
+// key-to-handlers maps are immutable snapshots behind atomic pointers that
+// registrations replace, so CBList takes no lock at all.
+//
+// Handler lists are extended in place past the length readers have seen,
+// which is invisible to them; removals always build a new list.
+type EventTableCOW struct {
+	mu     sync.Mutex // serializes writers
+	seed   maphash.Seed
+	shards [cowShards]atomic.Pointer[map[string]*cowList]
+	lastID uint64
+}
+
+func NewEventTableCOW() EventTableI {
+	return &EventTableCOW{seed: maphash.MakeSeed()}
+}
+
+func (t *EventTableCOW) shard(key string) *atomic.Pointer[map[string]*cowList] {
+	return &t.shards[maphash.String(t.seed, key)%cowShards]
+}
+
+func (t *EventTableCOW) CBList(key string) []CB {
+	m := t.shard(key).Load()
+	if m == nil {
+		return nil
+	}
+	l, ok := (*m)[key]
+	if !ok {
+		return nil
+	}
+	return *l.cbs.Load()
+}
+
+func (t *EventTableCOW) RegisterCB(key string, cb CB) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	l := t.list(key)
+	cbs := append(*l.cbs.Load(), cb)
+	if l.ids != nil {
+		l.ids = append(l.ids, 0)
+	}
+	l.cbs.Store(&cbs)
+}
+
+func (t *EventTableCOW) RegisterRemovableCB(key string, cb CB) (remove func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	l := t.list(key)
+	cbs := append(*l.cbs.Load(), cb)
+	for len(l.ids) < len(cbs)-1 {
+		l.ids = append(l.ids, 0)
+	}
+	t.lastID++
+	id := t.lastID
+	l.ids = append(l.ids, id)
+	l.cbs.Store(&cbs)
+	return func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		i := slices.Index(l.ids, id)
+		if i < 0 {
+			return
+		}
+		old := *l.cbs.Load()
+		cbs := append(old[:i:i], old[i+1:]...)
+		l.ids = append(l.ids[:i:i], l.ids[i+1:]...)
+		l.cbs.Store(&cbs)
+	}
+}
+
+// list returns the handler list of key, publishing a new snapshot of its
+// shard if key is new. t.mu must be held.
+func (t *EventTableCOW) list(key string) *cowList {
+	s := t.shard(key)
+	var m map[string]*cowList
+	if old := s.Load(); old != nil {
+		if l, ok := (*old)[key]; ok {
+			return l
+		}
+		m = maps.Clone(*old)
+	} else {
+		m = make(map[string]*cowList)
+	}
+	l := new(cowList)
+	l.cbs.Store(new([]CB))
+	m[key] = l
+	s.Store(&m)
+	return l
+}
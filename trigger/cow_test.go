@@ -0,0 +1,56 @@
+package trigger
+
+import (
+	"github.com/hyicode/utils/assert"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestEventTableCOW(t *testing.T) {
+	const (
+		KeyNum = 1000
+		CbNum  = 10
+	)
+	table := NewEventTableCOW()
+	keys := make([]EventName[int], 0, KeyNum)
+	for i := 0; i < KeyNum; i++ {
+		keys = append(keys, EventName[int]("cow_"+strconv.Itoa(i)))
+	}
+
+	var counter atomic.Int64
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; ; j++ {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				keys[j%KeyNum].Trigger(table, 1)
+			}
+		}()
+	}
+	for _, key := range keys {
+		for j := 0; j < CbNum; j++ {
+			key.On(table, func(event int) {
+				counter.Add(int64(event))
+			})
+		}
+	}
+	close(stop)
+	wg.Wait()
+
+	counter.Store(0)
+	for _, key := range keys {
+		assert.EqualFatalf(t, CbNum, len(table.CBList(string(key))), "%s", key)
+		key.Trigger(table, 1)
+	}
+	assert.EqualErrorf(t, int64(KeyNum*CbNum), counter.Load(), "counter")
+	assert.EqualErrorf(t, 0, len(table.CBList("missing")), "missing key")
+}
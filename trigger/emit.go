@@ -0,0 +1,207 @@
+package trigger
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// On and Emit use the payload type itself as the key, so events need no
+// EventName declaration and two types can't collide:
+//
+//	trigger.On(table, func(e OrderPaid) { ... })
+//	trigger.Emit(table, OrderPaid{ID: 1})
+//
+// Handlers registered for an interface type receive every emitted event
+// whose type implements it.
+
+// interfaces holds the interface types On was called with, in any table,
+// and caches which of them each emitted type implements.
+var interfaces struct {
+	mu    sync.RWMutex
+	types []reflect.Type
+	keys  map[reflect.Type][]string // emitted type -> keys to dispatch on
+}
+
+// TypeKey returns the key events of type T are registered and triggered
+// with by On and Emit.
+func TypeKey[T any]() string {
+	return typeKey(reflect.TypeFor[T]())
+}
+
+func typeKey(t reflect.Type) string {
+	if t.Kind() == reflect.Pointer {
+		return "*" + typeKey(t.Elem())
+	}
+	var b strings.Builder
+	b.WriteString("type:")
+	writeTypeName(&b, t)
+	return b.String()
+}
+
+// writeTypeName writes t like reflect.Type.String does, but qualifying named
+// types, unexported fields and unexported methods with their import path
+// rather than their package name, which two packages may share.
+func writeTypeName(b *strings.Builder, t reflect.Type) {
+	if t.Name() != "" {
+		if t.PkgPath() != "" {
+			b.WriteString(t.PkgPath() + ".")
+		}
+		b.WriteString(t.Name())
+		return
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		b.WriteString("*")
+		writeTypeName(b, t.Elem())
+	case reflect.Slice:
+		b.WriteString("[]")
+		writeTypeName(b, t.Elem())
+	case reflect.Array:
+		b.WriteString("[" + strconv.Itoa(t.Len()) + "]")
+		writeTypeName(b, t.Elem())
+	case reflect.Map:
+		b.WriteString("map[")
+		writeTypeName(b, t.Key())
+		b.WriteString("]")
+		writeTypeName(b, t.Elem())
+	case reflect.Chan:
+		switch t.ChanDir() {
+		case reflect.RecvDir:
+			b.WriteString("<-chan ")
+		case reflect.SendDir:
+			b.WriteString("chan<- ")
+		default:
+			b.WriteString("chan ")
+		}
+		writeTypeName(b, t.Elem())
+	case reflect.Func:
+		b.WriteString("func")
+		writeSignature(b, t)
+	case reflect.Struct:
+		b.WriteString("struct {")
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if i > 0 {
+				b.WriteString(";")
+			}
+			b.WriteString(" ")
+			if !f.Anonymous {
+				if f.PkgPath != "" {
+					b.WriteString(f.PkgPath + ".")
+				}
+				b.WriteString(f.Name + " ")
+			}
+			writeTypeName(b, f.Type)
+			if f.Tag != "" {
+				b.WriteString(" " + strconv.Quote(string(f.Tag)))
+			}
+		}
+		b.WriteString(" }")
+	case reflect.Interface:
+		b.WriteString("interface {")
+		for i := 0; i < t.NumMethod(); i++ {
+			m := t.Method(i)
+			if i > 0 {
+				b.WriteString(";")
+			}
+			b.WriteString(" ")
+			if m.PkgPath != "" {
+				b.WriteString(m.PkgPath + ".")
+			}
+			b.WriteString(m.Name)
+			writeSignature(b, m.Type)
+		}
+		b.WriteString(" }")
+	default:
+		b.WriteString(t.String())
+	}
+}
+
+func writeSignature(b *strings.Builder, t reflect.Type) {
+	b.WriteString("(")
+	for i := 0; i < t.NumIn(); i++ {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		if t.IsVariadic() && i == t.NumIn()-1 {
+			b.WriteString("...")
+			writeTypeName(b, t.In(i).Elem())
+		} else {
+			writeTypeName(b, t.In(i))
+		}
+	}
+	b.WriteString(")")
+	if t.NumOut() > 0 {
+		b.WriteString(" (")
+		for i := 0; i < t.NumOut(); i++ {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			writeTypeName(b, t.Out(i))
+		}
+		b.WriteString(")")
+	}
+}
+
+// On registers cb for the events of type T.
+func On[T any](t EventTableI, cb func(event T)) error {
+	typ := reflect.TypeFor[T]()
+	if typ.Kind() == reflect.Interface {
+		addInterface(typ)
+	}
+	return EventName[T](typeKey(typ)).On(t, cb)
+}
+
+// Emit triggers event on the handlers of its type and of the interfaces it
+// implements. When T is an interface, the dynamic type of event is used.
+func Emit[T any](t EventTableI, event T) error {
+	typ := reflect.TypeFor[T]()
+	if typ.Kind() == reflect.Interface {
+		if dyn := reflect.TypeOf(event); dyn != nil {
+			typ = dyn
+		}
+	}
+	for _, key := range emitKeys(typ) {
+		if err := Dispatch(t, key, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func addInterface(typ reflect.Type) {
+	interfaces.mu.Lock()
+	defer interfaces.mu.Unlock()
+	for _, t := range interfaces.types {
+		if t == typ {
+			return
+		}
+	}
+	interfaces.types = append(interfaces.types, typ)
+	interfaces.keys = nil
+}
+
+func emitKeys(typ reflect.Type) []string {
+	interfaces.mu.RLock()
+	keys, ok := interfaces.keys[typ]
+	interfaces.mu.RUnlock()
+	if ok {
+		return keys
+	}
+
+	interfaces.mu.Lock()
+	defer interfaces.mu.Unlock()
+	keys = []string{typeKey(typ)}
+	for _, iface := range interfaces.types {
+		if iface != typ && typ.Implements(iface) {
+			keys = append(keys, typeKey(iface))
+		}
+	}
+	if interfaces.keys == nil {
+		interfaces.keys = make(map[reflect.Type][]string)
+	}
+	interfaces.keys[typ] = keys
+	return keys
+}
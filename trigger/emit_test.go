@@ -0,0 +1,63 @@
+package trigger
+
+import (
+	"fmt"
+	"github.com/hyicode/utils/assert"
+	"math/rand"
+	randv2 "math/rand/v2"
+	"testing"
+)
+
+type orderPaid struct {
+	ID     int
+	Amount int
+}
+
+func (e orderPaid) String() string { return fmt.Sprintf("paid %d", e.ID) }
+
+type orderShipped struct {
+	ID int
+}
+
+func (e *orderShipped) String() string { return fmt.Sprintf("shipped %d", e.ID) }
+
+func TestEmit(t *testing.T) {
+	table := NewEventTable()
+
+	var paid []int
+	On(table, func(event orderPaid) { paid = append(paid, event.Amount) })
+	var shipped []int
+	On(table, func(event *orderShipped) { shipped = append(shipped, event.ID) })
+	var described []string
+	On(table, func(event fmt.Stringer) { described = append(described, event.String()) })
+	all := 0
+	On(table, func(event any) { all++ })
+
+	Emit(table, orderPaid{ID: 1, Amount: 10})
+	Emit(table, &orderShipped{ID: 2})
+	Emit(table, orderShipped{ID: 3}) // only *orderShipped is a Stringer
+	var s fmt.Stringer = orderPaid{ID: 4, Amount: 40}
+	Emit(table, s)
+	Emit(table, 5)
+
+	expectInts(t, "paid", []int{10, 40}, paid)
+	expectInts(t, "shipped", []int{2}, shipped)
+	expectKeys(t, "described", []string{"paid 1", "shipped 2", "paid 4"}, described)
+	assert.EqualErrorf(t, 5, all, "any")
+}
+
+func TestTypeKey(t *testing.T) {
+	assert.EqualErrorf(t, "type:github.com/hyicode/utils/trigger.orderPaid", TypeKey[orderPaid](), "named")
+	assert.EqualErrorf(t, "*type:github.com/hyicode/utils/trigger.orderShipped", TypeKey[*orderShipped](), "pointer")
+	assert.EqualErrorf(t, "type:[]int", TypeKey[[]int](), "unnamed")
+	assert.EqualErrorf(t, "type:int", TypeKey[int](), "builtin")
+	assert.EqualErrorf(t, "type:map[string][]*math/rand/v2.Rand", TypeKey[map[string][]*randv2.Rand](), "composite")
+	// both packages are named rand
+	assert.EqualErrorf(t, false, TypeKey[[]rand.Rand]() == TypeKey[[]randv2.Rand](), "same package name")
+	assert.EqualErrorf(t, false, TypeKey[struct{ R rand.Rand }]() == TypeKey[struct{ R randv2.Rand }](), "struct fields")
+	assert.EqualErrorf(t, false, TypeKey[func(...*rand.Rand) error]() == TypeKey[func(...*randv2.Rand) error](), "func")
+	assert.EqualErrorf(t, `type:struct { github.com/hyicode/utils/trigger.id int "key:\"id\"" }`,
+		TypeKey[struct {
+			id int `key:"id"`
+		}](), "unexported field")
+}
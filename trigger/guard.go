@@ -0,0 +1,106 @@
+package trigger
+
+import (
+	"errors"
+	"strings"
+)
+
+// CascadeError reports a trigger nested deeper than GuardConfig.MaxDepth.
+type CascadeError struct {
+	Chain []string // keys from the outermost trigger to the refused one
+}
+
+func (e *CascadeError) Error() string {
+	return "trigger: cascade too deep: " + strings.Join(e.Chain, " -> ")
+}
+
+type GuardConfig struct {
+	MaxDepth int // defaults to 16
+	// Queue defers the events triggered by handlers until the current
+	// dispatch is over, instead of dispatching them recursively. They are
+	// dispatched in the order they were triggered.
+	Queue bool
+	// OnError is called with every error met, since errors returned to a
+	// handler triggering an event are easily lost.
+	OnError func(err error)
+}
+
+type guardedEvent struct {
+	key   string
+	event any
+	chain []string
+}
+
+// GuardedEventTable bounds the nesting of triggers made by handlers, so that
+// an event re-triggering itself through other events fails with a
+// *CascadeError instead of overflowing the stack.
+//
+// The nesting is tracked per table rather than per call chain, so events must
+// only be triggered on it from one goroutine, like a game loop: concurrent
+// triggers would be taken for nested ones.
+type GuardedEventTable struct {
+	table EventTableI
+	cfg   GuardConfig
+
+	chain []string // keys being dispatched, outermost first
+	queue []guardedEvent
+}
+
+func NewGuardedEventTable(table EventTableI, cfg GuardConfig) *GuardedEventTable {
+	if cfg.MaxDepth <= 0 {
+		cfg.MaxDepth = 16
+	}
+	return &GuardedEventTable{table: table, cfg: cfg}
+}
+
+func (t *GuardedEventTable) RegisterCB(key string, cb CB) {
+	t.table.RegisterCB(key, cb)
+}
+
+func (t *GuardedEventTable) RegisterRemovableCB(key string, cb CB) (remove func()) {
+	return RegisterRemovable(t.table, key, cb)
+}
+
+func (t *GuardedEventTable) CBList(key string) []CB {
+	return t.table.CBList(key)
+}
+
+func (t *GuardedEventTable) Dispatch(key string, event any) error {
+	chain := append(t.chain[:len(t.chain):len(t.chain)], key)
+	if len(chain) > t.cfg.MaxDepth {
+		return t.report(&CascadeError{Chain: chain})
+	}
+	if t.cfg.Queue && len(t.chain) > 0 {
+		t.queue = append(t.queue, guardedEvent{key: key, event: event, chain: chain})
+		return nil
+	}
+	outer := t.chain
+	t.chain = chain
+
+	defer func() {
+		t.chain = outer
+		if len(outer) == 0 {
+			t.queue = nil // only left behind by a panicking handler
+		}
+	}()
+
+	err := t.report(Dispatch(t.table, key, event))
+	if !t.cfg.Queue {
+		return err
+	}
+	errs := []error{err}
+	for len(t.queue) > 0 {
+		next := t.queue[0]
+		t.queue = t.queue[1:]
+		t.chain = next.chain
+		errs = append(errs, t.report(Dispatch(t.table, next.key, next.event)))
+	}
+	return errors.Join(errs...)
+}
+
+func (t *GuardedEventTable) report(err error) error {
+	if err != nil && t.cfg.OnError != nil {
+		t.cfg.OnError(err)
+	}
+	return err
+}
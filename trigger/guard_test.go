@@ -0,0 +1,88 @@
+package trigger
+
+import (
+	"errors"
+	"github.com/hyicode/utils/assert"
+	"strings"
+	"testing"
+)
+
+func TestGuardedEventTableRecursive(t *testing.T) {
+	const (
+		Ping EventName[int] = "ping"
+		Pong EventName[int] = "pong"
+	)
+	var reported []error
+	table := NewGuardedEventTable(NewEventTable(), GuardConfig{
+		MaxDepth: 5,
+		OnError:  func(err error) { reported = append(reported, err) },
+	})
+	calls := 0
+	var refused error
+	Ping.On(table, func(event int) {
+		calls++
+		if err := Pong.Trigger(table, event+1); err != nil {
+			refused = err
+		}
+	})
+	Pong.On(table, func(event int) {
+		calls++
+		if err := Ping.Trigger(table, event+1); err != nil {
+			refused = err
+		}
+	})
+
+	assert.EqualFatalf(t, nil, Ping.Trigger(table, 0), "outermost trigger")
+	assert.EqualErrorf(t, 5, calls, "calls")
+	var cascade *CascadeError
+	assert.EqualFatalf(t, true, errors.As(refused, &cascade), "refused: %v", refused)
+	assert.EqualErrorf(t, "ping pong ping pong ping pong", strings.Join(cascade.Chain, " "), "chain")
+	assert.EqualFatalf(t, 1, len(reported), "reported")
+	assert.EqualErrorf(t, refused, reported[0], "reported")
+
+	calls = 0
+	Ping.Trigger(table, 0)
+	assert.EqualErrorf(t, 5, calls, "depth reset after dispatch")
+}
+
+func TestGuardedEventTableQueue(t *testing.T) {
+	const (
+		A EventName[string] = "a"
+		B EventName[string] = "b"
+		C EventName[string] = "c"
+		D EventName[string] = "d"
+	)
+	table := NewGuardedEventTable(NewEventTable(), GuardConfig{Queue: true, MaxDepth: 3})
+	var log []string
+	A.On(table, func(event string) {
+		log = append(log, "a")
+		B.Trigger(table, "")
+		C.Trigger(table, "")
+		log = append(log, "a done")
+	})
+	B.On(table, func(event string) {
+		log = append(log, "b")
+		D.Trigger(table, "")
+	})
+	C.On(table, func(event string) { log = append(log, "c") })
+	D.On(table, func(event string) {
+		log = append(log, "d")
+		A.Trigger(table, "")
+	})
+
+	err := A.Trigger(table, "")
+	expectKeys(t, "queue", []string{"a", "a done", "b", "c", "d"}, log)
+	assert.EqualErrorf(t, nil, err, "queued triggers")
+
+	var refused error
+	D.On(table, func(event string) {
+		refused = A.Trigger(table, "")
+	})
+	D.Trigger(table, "")
+	var cascade *CascadeError
+	assert.EqualFatalf(t, false, errors.As(refused, &cascade), "depth 2 is allowed: %v", refused)
+	log = nil
+	A.Trigger(table, "")
+	assert.EqualFatalf(t, true, errors.As(refused, &cascade), "refused: %v", refused)
+	assert.EqualErrorf(t, "a b d a", strings.Join(cascade.Chain, " "), "chain")
+}
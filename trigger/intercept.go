@@ -0,0 +1,73 @@
+package trigger
+
+// DispatchInterceptor wraps the dispatch of one event, next delivers it to
+// the handlers. A tracing span around EventName.Trigger is a typical one.
+type DispatchInterceptor func(key string, event any, next func() error) error
+
+// HandlerInterceptor wraps one handler call, next runs the handler.
+type HandlerInterceptor func(key string, event any, next func())
+
+// Interceptor groups the hooks of one concern, either may be nil.
+type Interceptor struct {
+	Dispatch DispatchInterceptor
+	Handler  HandlerInterceptor
+}
+
+// InterceptedEventTable runs interceptors around every dispatch and every
+// handler call on table, the first interceptor being the outermost. Handler
+// interceptors are attached when a handler is registered, so they also run
+// for dispatches made directly on table, e.g. by an AsyncEventTable worker.
+type InterceptedEventTable struct {
+	table    EventTableI
+	dispatch []DispatchInterceptor
+	handler  []HandlerInterceptor
+}
+
+func NewInterceptedEventTable(table EventTableI, interceptors ...Interceptor) *InterceptedEventTable {
+	t := &InterceptedEventTable{table: table}
+	for _, i := range interceptors {
+		if i.Dispatch != nil {
+			t.dispatch = append(t.dispatch, i.Dispatch)
+		}
+		if i.Handler != nil {
+			t.handler = append(t.handler, i.Handler)
+		}
+	}
+	return t
+}
+
+func (t *InterceptedEventTable) RegisterCB(key string, cb CB) {
+	t.table.RegisterCB(key, t.wrap(key, cb))
+}
+
+func (t *InterceptedEventTable) RegisterRemovableCB(key string, cb CB) (remove func()) {
+	return RegisterRemovable(t.table, key, t.wrap(key, cb))
+}
+
+func (t *InterceptedEventTable) CBList(key string) []CB {
+	return t.table.CBList(key)
+}
+
+func (t *InterceptedEventTable) Dispatch(key string, event any) error {
+	next := func() error { return Dispatch(t.table, key, event) }
+	for i := len(t.dispatch) - 1; i >= 0; i-- {
+		interceptor, inner := t.dispatch[i], next
+		next = func() error { return interceptor(key, event, inner) }
+	}
+	return next()
+}
+
+func (t *InterceptedEventTable) wrap(key string, cb CB) CB {
+	if len(t.handler) == 0 {
+		return cb
+	}
+	interceptors := t.handler
+	return func(event any) {
+		next := func() { cb(event) }
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, inner := interceptors[i], next
+			next = func() { interceptor(key, event, inner) }
+		}
+		next()
+	}
+}
@@ -0,0 +1,99 @@
+package trigger
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"github.com/hyicode/utils/assert"
+	"github.com/hyicode/utils/clock"
+	"testing"
+	"time"
+)
+
+func TestInterceptedEventTableOrder(t *testing.T) {
+	const key EventName[int] = "intercept"
+	var log []string
+	trace := func(name string) Interceptor {
+		return Interceptor{
+			Dispatch: func(key string, event any, next func() error) error {
+				log = append(log, name+" dispatch "+key)
+				return next()
+			},
+			Handler: func(key string, event any, next func()) {
+				log = append(log, name+" handler")
+				next()
+			},
+		}
+	}
+	table := NewInterceptedEventTable(NewEventTable(), trace("outer"), trace("inner"))
+	key.On(table, func(event int) { log = append(log, "handler") })
+	key.Trigger(table, 1)
+
+	expect := []string{
+		"outer dispatch intercept",
+		"inner dispatch intercept",
+		"outer handler",
+		"inner handler",
+		"handler",
+	}
+	assert.EqualFatalf(t, len(expect), len(log), "%v", log)
+	for i := range expect {
+		assert.EqualErrorf(t, expect[i], log[i], "%v", log)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	const (
+		Fast EventName[int] = "fast"
+		Slow EventName[int] = "slow"
+	)
+	clk := clock.NewFake(time.Unix(0, 0))
+	bounds := []time.Duration{10 * time.Millisecond, time.Millisecond}
+	metrics := NewMetrics(clk, bounds...)
+	bounds[0] = time.Hour
+	closed := NewAsyncEventTable(nil, AsyncConfig{Workers: 1})
+	closed.Close()
+	table := NewInterceptedEventTable(NewEventTable(), metrics.Interceptor())
+
+	Fast.On(table, func(event int) {})
+	Fast.On(table, func(event int) {})
+	Slow.On(table, func(event int) {
+		clk.Advance(5 * time.Millisecond)
+		if event < 0 {
+			panic("negative")
+		}
+	})
+	for i := 0; i < 3; i++ {
+		Fast.Trigger(table, i)
+	}
+	Slow.Trigger(table, 1)
+	func() {
+		defer func() {
+			assert.EqualErrorf(t, "negative", recover(), "panic is propagated")
+		}()
+		Slow.Trigger(table, -1)
+	}()
+	EventName[int]("fail").Trigger(NewInterceptedEventTable(closed, metrics.Interceptor()), 1)
+
+	stats := metrics.Snapshot()
+	fast, slow, fail := stats["fast"], stats["slow"], stats["fail"]
+	assert.EqualErrorf(t, uint64(3), fast.Triggers, "fast triggers")
+	assert.EqualErrorf(t, uint64(6), fast.Handlers, "fast handlers")
+	assert.EqualErrorf(t, uint64(6), fast.Latency.Counts[0], "fast latency")
+	assert.EqualErrorf(t, uint64(2), slow.Triggers, "slow triggers")
+	assert.EqualErrorf(t, uint64(1), slow.Panics, "slow panics")
+	assert.EqualErrorf(t, uint64(2), slow.Latency.Counts[1], "slow latency")
+	assert.EqualErrorf(t, 10*time.Millisecond, slow.Latency.Sum, "slow latency sum")
+	assert.EqualErrorf(t, uint64(1), fail.Errors, "fail errors")
+	assert.DeepEqualErrorf(t, []time.Duration{time.Millisecond, 10 * time.Millisecond}, fast.Latency.Bounds, "sorted bounds")
+	fast.Latency.Bounds[0] = time.Hour
+	assert.EqualErrorf(t, time.Millisecond, metrics.Snapshot()["fast"].Latency.Bounds[0], "bounds copied")
+
+	// expvar names can't be reused, and the test may run several times
+	name := fmt.Sprintf("trigger_test_metrics_%d", time.Now().UnixNano())
+	metrics.Publish(name)
+	var exported map[string]EventStats
+	err := json.Unmarshal([]byte(expvar.Get(name).String()), &exported)
+	assert.EqualFatalf(t, nil, err, "expvar json")
+	assert.EqualErrorf(t, uint64(3), exported["fast"].Triggers, "exported triggers")
+}
@@ -0,0 +1,133 @@
+package trigger
+
+import (
+	"expvar"
+	"github.com/hyicode/utils/clock"
+	"slices"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultLatencyBounds are the upper bounds of the handler latency buckets
+// used when NewMetrics is given none.
+var DefaultLatencyBounds = []time.Duration{
+	10 * time.Microsecond,
+	100 * time.Microsecond,
+	time.Millisecond,
+	10 * time.Millisecond,
+	100 * time.Millisecond,
+	time.Second,
+}
+
+// Metrics collects per-key statistics through its Interceptor.
+type Metrics struct {
+	clock  clock.Clock
+	bounds []time.Duration
+	keys   sync.Map // key -> *keyMetrics
+}
+
+type keyMetrics struct {
+	triggers atomic.Uint64
+	errors   atomic.Uint64
+	handlers atomic.Uint64
+	panics   atomic.Uint64
+	sum      atomic.Int64
+	buckets  []atomic.Uint64 // len(bounds)+1, the last one unbounded
+}
+
+type EventStats struct {
+	Triggers uint64 // dispatches
+	Errors   uint64 // dispatches returning an error
+	Handlers uint64 // handler calls
+	Panics   uint64 // handler calls that panicked
+	Latency  Histogram
+}
+
+// Histogram of handler latencies: Counts[i] handler calls took at most
+// Bounds[i], and Counts[len(Bounds)] took longer than all of them.
+type Histogram struct {
+	Bounds []time.Duration
+	Counts []uint64
+	Sum    time.Duration
+}
+
+// NewMetrics measures handler latencies on clk, nil meaning clock.Real,
+// bucketing them by bounds, in any order.
+func NewMetrics(clk clock.Clock, bounds ...time.Duration) *Metrics {
+	if len(bounds) == 0 {
+		bounds = DefaultLatencyBounds
+	}
+	bounds = slices.Clone(bounds)
+	slices.Sort(bounds)
+	return &Metrics{clock: orRealClock(clk), bounds: bounds}
+}
+
+func (m *Metrics) Interceptor() Interceptor {
+	return Interceptor{
+		Dispatch: func(key string, event any, next func() error) error {
+			km := m.key(key)
+			km.triggers.Add(1)
+			err := next()
+			if err != nil {
+				km.errors.Add(1)
+			}
+			return err
+		},
+		Handler: func(key string, event any, next func()) {
+			km := m.key(key)
+			km.handlers.Add(1)
+			start := m.clock.Now()
+			defer func() {
+				d := m.clock.Now().Sub(start)
+				km.sum.Add(int64(d))
+				km.buckets[sort.Search(len(m.bounds), func(i int) bool { return d <= m.bounds[i] })].Add(1)
+				if r := recover(); r != nil {
+					km.panics.Add(1)
+					panic(r)
+				}
+			}()
+			next()
+		},
+	}
+}
+
+func (m *Metrics) key(key string) *keyMetrics {
+	if km, ok := m.keys.Load(key); ok {
+		return km.(*keyMetrics)
+	}
+	km, _ := m.keys.LoadOrStore(key, &keyMetrics{buckets: make([]atomic.Uint64, len(m.bounds)+1)})
+	return km.(*keyMetrics)
+}
+
+// Snapshot returns the statistics collected so far by key.
+func (m *Metrics) Snapshot() map[string]EventStats {
+	stats := make(map[string]EventStats)
+	m.keys.Range(func(k, v any) bool {
+		km := v.(*keyMetrics)
+		s := EventStats{
+			Triggers: km.triggers.Load(),
+			Errors:   km.errors.Load(),
+			Handlers: km.handlers.Load(),
+			Panics:   km.panics.Load(),
+			Latency: Histogram{
+				Bounds: slices.Clone(m.bounds),
+				Counts: make([]uint64, len(km.buckets)),
+				Sum:    time.Duration(km.sum.Load()),
+			},
+		}
+		for i := range km.buckets {
+			s.Latency.Counts[i] = km.buckets[i].Load()
+		}
+		stats[k.(string)] = s
+		return true
+	})
+	return stats
+}
+
+// Publish exports the snapshot as the expvar variable name. Like
+// expvar.Publish, it panics if name is already in use.
+func (m *Metrics) Publish(name string) {
+	expvar.Publish(name, expvar.Func(func() any { return m.Snapshot() }))
+}
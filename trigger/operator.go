@@ -0,0 +1,162 @@
+package trigger
+
+import (
+	"github.com/hyicode/utils/clock"
+	"sync"
+	"time"
+)
+
+// The operators below wrap a handler to tame bursts of events, e.g.
+//
+//	ConfigReloaded.On(table, Debounce(nil, time.Second, reload))
+//
+// A nil clock means clock.Real. Calls delayed by an operator run on the
+// clock's goroutine rather than the triggering one.
+
+// Debounce calls fn with the last event of a burst, once no event arrived
+// for wait.
+func Debounce[T any](clk clock.Clock, wait time.Duration, fn func(T)) func(T) {
+	clk = orRealClock(clk)
+	var (
+		mu    sync.Mutex
+		last  T
+		gen   uint64
+		timer clock.Timer
+	)
+	return func(event T) {
+		mu.Lock()
+		defer mu.Unlock()
+		last = event
+		gen++
+		if timer != nil {
+			timer.Stop()
+		}
+		my := gen
+		timer = clk.AfterFunc(wait, func() {
+			mu.Lock()
+			if my != gen {
+				mu.Unlock()
+				return
+			}
+			v := last
+			timer = nil
+			mu.Unlock()
+			fn(v)
+		})
+	}
+}
+
+// DebounceLeading calls fn with the first event of a burst and ignores the
+// following ones until no event arrived for wait.
+func DebounceLeading[T any](clk clock.Clock, wait time.Duration, fn func(T)) func(T) {
+	clk = orRealClock(clk)
+	var (
+		mu   sync.Mutex
+		last time.Time
+		seen bool
+	)
+	return func(event T) {
+		now := clk.Now()
+		mu.Lock()
+		leading := !seen || now.Sub(last) >= wait
+		last, seen = now, true
+		mu.Unlock()
+		if leading {
+			fn(event)
+		}
+	}
+}
+
+// Throttle lets at most n events through in any interval and drops the
+// others.
+func Throttle[T any](clk clock.Clock, n int, interval time.Duration, fn func(T)) func(T) {
+	clk = orRealClock(clk)
+	n = max(n, 1)
+	var (
+		mu     sync.Mutex
+		passed = make([]time.Time, 0, n) // ring of the last n pass times
+		next   int
+	)
+	return func(event T) {
+		now := clk.Now()
+		mu.Lock()
+		if len(passed) < n {
+			passed = append(passed, now)
+		} else if now.Sub(passed[next]) >= interval {
+			passed[next] = now
+			next = (next + 1) % n
+		} else {
+			mu.Unlock()
+			return
+		}
+		mu.Unlock()
+		fn(event)
+	}
+}
+
+// Batch collects events and calls fn with them once size events are
+// collected or window has passed since the first one, whichever comes
+// first. A window of 0 only delivers full batches. flush delivers what is
+// collected so far right away, e.g. on shutdown.
+//
+// Batches are delivered one at a time and in order, whichever goroutine
+// completes them, so fn must not call handler or flush.
+func Batch[T any](clk clock.Clock, size int, window time.Duration, fn func([]T)) (handler func(T), flush func()) {
+	clk = orRealClock(clk)
+	var (
+		mu      sync.Mutex
+		deliver sync.Mutex // held across fn, taken with mu held to keep the order
+		buf     []T
+		gen     uint64
+		timer   clock.Timer
+	)
+	// take empties buf and delivers its content. mu must be held, take
+	// releases it.
+	take := func() {
+		batch := buf
+		buf = nil
+		gen++
+		if timer != nil {
+			timer.Stop()
+			timer = nil
+		}
+		deliver.Lock()
+		defer deliver.Unlock()
+		mu.Unlock()
+		if len(batch) > 0 {
+			fn(batch)
+		}
+	}
+	handler = func(event T) {
+		mu.Lock()
+		buf = append(buf, event)
+		if len(buf) >= size {
+			take()
+			return
+		}
+		if len(buf) == 1 && window > 0 {
+			my := gen
+			timer = clk.AfterFunc(window, func() {
+				mu.Lock()
+				if my != gen {
+					mu.Unlock()
+					return
+				}
+				take()
+			})
+		}
+		mu.Unlock()
+	}
+	flush = func() {
+		mu.Lock()
+		take()
+	}
+	return handler, flush
+}
+
+func orRealClock(clk clock.Clock) clock.Clock {
+	if clk == nil {
+		return clock.Real
+	}
+	return clk
+}
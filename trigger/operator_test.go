@@ -0,0 +1,116 @@
+package trigger
+
+import (
+	"github.com/hyicode/utils/assert"
+	"github.com/hyicode/utils/clock"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDebounce(t *testing.T) {
+	const key EventName[int] = "debounce"
+	clk := clock.NewFake(time.Unix(0, 0))
+	table := NewEventTable()
+
+	var trailing, leading []int
+	key.On(table, Debounce(clk, 100*time.Millisecond, func(event int) {
+		trailing = append(trailing, event)
+	}))
+	key.On(table, DebounceLeading(clk, 100*time.Millisecond, func(event int) {
+		leading = append(leading, event)
+	}))
+
+	for i := 1; i <= 5; i++ {
+		key.Trigger(table, i)
+		clk.Advance(50 * time.Millisecond)
+	}
+	assert.EqualFatalf(t, 0, len(trailing), "trailing during burst")
+	clk.Advance(50 * time.Millisecond)
+	key.Trigger(table, 6)
+	clk.Advance(time.Second)
+
+	expectInts(t, "trailing", []int{5, 6}, trailing)
+	expectInts(t, "leading", []int{1, 6}, leading)
+}
+
+func TestThrottle(t *testing.T) {
+	const key EventName[int] = "throttle"
+	clk := clock.NewFake(time.Unix(0, 0))
+	table := NewEventTable()
+
+	var got []int
+	key.On(table, Throttle(clk, 2, time.Second, func(event int) {
+		got = append(got, event)
+	}))
+	for i := 0; i < 10; i++ {
+		key.Trigger(table, i)
+		clk.Advance(300 * time.Millisecond)
+	}
+	// passes at 0ms, 300ms, 1200ms, 1500ms, 2400ms, 2700ms
+	expectInts(t, "throttle", []int{0, 1, 4, 5, 8, 9}, got)
+}
+
+func TestBatch(t *testing.T) {
+	const key EventName[int] = "batch"
+	clk := clock.NewFake(time.Unix(0, 0))
+	table := NewEventTable()
+
+	var got [][]int
+	handler, flush := Batch(clk, 3, time.Second, func(events []int) {
+		got = append(got, events)
+	})
+	key.On(table, handler)
+
+	for i := 0; i < 4; i++ {
+		key.Trigger(table, i)
+	}
+	assert.EqualFatalf(t, 1, len(got), "full batch")
+	clk.Advance(999 * time.Millisecond)
+	assert.EqualFatalf(t, 1, len(got), "before window")
+	clk.Advance(time.Millisecond)
+	assert.EqualFatalf(t, 2, len(got), "window")
+	key.Trigger(table, 4)
+	flush()
+	flush()
+	clk.Advance(time.Hour)
+
+	assert.EqualFatalf(t, 3, len(got), "batches %v", got)
+	expectInts(t, "batch 0", []int{0, 1, 2}, got[0])
+	expectInts(t, "batch 1", []int{3}, got[1])
+	expectInts(t, "batch 2", []int{4}, got[2])
+}
+
+func TestBatchOrder(t *testing.T) {
+	var (
+		got     []int
+		running atomic.Bool
+	)
+	handler, flush := Batch(nil, 5, 10*time.Microsecond, func(events []int) {
+		if running.Swap(true) {
+			t.Error("concurrent batches")
+		}
+		time.Sleep(time.Microsecond)
+		got = append(got, events...)
+		running.Store(false)
+	})
+	const n = 2000
+	for i := 0; i < n; i++ {
+		handler(i)
+		if i%7 == 0 {
+			time.Sleep(10 * time.Microsecond)
+		}
+	}
+	flush()
+	assert.EqualFatalf(t, n, len(got), "delivered")
+	for i, v := range got {
+		assert.EqualFatalf(t, i, v, "order")
+	}
+}
+
+func expectInts(t *testing.T, name string, expect, actual []int) {
+	assert.EqualFatalf(t, len(expect), len(actual), "%s len %v", name, actual)
+	for i := range expect {
+		assert.EqualErrorf(t, expect[i], actual[i], "%s %v", name, actual)
+	}
+}
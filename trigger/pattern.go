@@ -0,0 +1,231 @@
+package trigger
+
+import (
+	"slices"
+	"strings"
+	"sync"
+)
+
+// PatternSep separates the segments of hierarchical event names.
+const PatternSep = "."
+
+// maxPatternCache bounds the number of resolved keys kept by a
+// PatternEventTable, so tables triggered with unbounded key sets (ids in
+// names) don't grow forever.
+const maxPatternCache = 4096
+
+// PatternCB is a handler registered for a pattern. It receives the key the
+// event was triggered with along with the concrete event.
+type PatternCB func(key string, event any)
+
+type PatternEventTableI interface {
+	EventTableI
+	RegisterPatternCB(pattern string, cb PatternCB)
+}
+
+// EventPattern matches event names segment by segment: "*" matches exactly
+// one segment and "**" matches any number of segments, including none, so
+// "order.*" matches "order.paid" and "order.**" also matches "order" and
+// "order.item.added". Events whose payload is not a T are skipped, use
+// EventPattern[any] to receive everything.
+type EventPattern[T EventI] string
+
+func (p EventPattern[T]) On(t PatternEventTableI, cb func(key string, event T)) {
+	t.RegisterPatternCB(string(p), func(key string, event any) {
+		if v, ok := event.(T); ok {
+			cb(key, v)
+		}
+	})
+}
+
+type patternNode struct {
+	children map[string]*patternNode
+	cbs      []PatternCB
+	ids      []uint64 // parallel to cbs, 0 for the CBs that can't be removed
+}
+
+func (n *patternNode) child(seg string) *patternNode {
+	if n.children == nil {
+		n.children = make(map[string]*patternNode)
+	}
+	c, ok := n.children[seg]
+	if !ok {
+		c = new(patternNode)
+		n.children[seg] = c
+	}
+	return c
+}
+
+// match appends to out every node under n that completes a pattern matching segs.
+func (n *patternNode) match(segs []string, out []*patternNode) []*patternNode {
+	if len(segs) == 0 {
+		if len(n.cbs) > 0 && !containsNode(out, n) {
+			out = append(out, n)
+		}
+	} else {
+		if c, ok := n.children[segs[0]]; ok {
+			out = c.match(segs[1:], out)
+		}
+		if c, ok := n.children["*"]; ok {
+			out = c.match(segs[1:], out)
+		}
+	}
+	if c, ok := n.children["**"]; ok {
+		for i := 0; i <= len(segs); i++ {
+			out = c.match(segs[i:], out)
+		}
+	}
+	return out
+}
+
+func containsNode(nodes []*patternNode, n *patternNode) bool {
+	for _, v := range nodes {
+		if v == n {
+			return true
+		}
+	}
+	return false
+}
+
+func hasWildcard(key string) bool {
+	for _, seg := range strings.Split(key, PatternSep) {
+		if seg == "*" || seg == "**" {
+			return true
+		}
+	}
+	return false
+}
+
+// PatternEventTable is a concurrency-safe table that accepts both exact keys
+// and EventPattern subscriptions. Patterns live in a trie keyed by segment,
+// and the handlers resolved for a key are cached until the next registration
+// or removal.
+type PatternEventTable struct {
+	mu     sync.RWMutex
+	exact  EventTable
+	root   patternNode
+	cache  map[string][]CB
+	lastID uint64
+}
+
+func NewPatternEventTable() *PatternEventTable {
+	return &PatternEventTable{
+		exact: EventTable{cbList: make(map[string][]CB)},
+		cache: make(map[string][]CB),
+	}
+}
+
+// RegisterCB registers cb for key. A key containing "*" or "**" segments is
+// registered as a pattern.
+func (t *PatternEventTable) RegisterCB(key string, cb CB) {
+	if hasWildcard(key) {
+		t.RegisterPatternCB(key, func(_ string, event any) { cb(event) })
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.exact.RegisterCB(key, cb)
+	delete(t.cache, key)
+}
+
+func (t *PatternEventTable) RegisterPatternCB(pattern string, cb PatternCB) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.registerPattern(pattern, cb, 0)
+}
+
+// RegisterRemovableCB is RegisterCB returning a func that removes cb again.
+func (t *PatternEventTable) RegisterRemovableCB(key string, cb CB) (remove func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !hasWildcard(key) {
+		removeCB := t.exact.RegisterRemovableCB(key, cb)
+		delete(t.cache, key)
+		return func() {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			removeCB()
+			delete(t.cache, key)
+		}
+	}
+	t.lastID++
+	id := t.lastID
+	t.registerPattern(key, func(_ string, event any) { cb(event) }, id)
+	return func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		t.removePattern(key, id)
+	}
+}
+
+func (t *PatternEventTable) registerPattern(pattern string, cb PatternCB, id uint64) {
+	n := &t.root
+	for _, seg := range strings.Split(pattern, PatternSep) {
+		n = n.child(seg)
+	}
+	n.cbs = append(n.cbs, cb)
+	n.ids = append(n.ids, id)
+	clear(t.cache)
+}
+
+// removePattern builds new slices rather than shifting in place, like
+// EventTable.removeCB, and prunes the nodes left empty.
+func (t *PatternEventTable) removePattern(pattern string, id uint64) {
+	segs := strings.Split(pattern, PatternSep)
+	path := []*patternNode{&t.root}
+	for _, seg := range segs {
+		c, ok := path[len(path)-1].children[seg]
+		if !ok {
+			return
+		}
+		path = append(path, c)
+	}
+	n := path[len(path)-1]
+	i := slices.Index(n.ids, id)
+	if i < 0 {
+		return
+	}
+	n.cbs = append(n.cbs[:i:i], n.cbs[i+1:]...)
+	n.ids = append(n.ids[:i:i], n.ids[i+1:]...)
+	for j := len(segs); j > 0 && len(path[j].cbs) == 0 && len(path[j].children) == 0; j-- {
+		delete(path[j-1].children, segs[j-1])
+	}
+	clear(t.cache)
+}
+
+func (t *PatternEventTable) CBList(key string) []CB {
+	t.mu.RLock()
+	cbs, ok := t.cache[key]
+	t.mu.RUnlock()
+	if ok {
+		return cbs
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if cbs, ok := t.cache[key]; ok {
+		return cbs
+	}
+	cbs = t.resolve(key)
+	if len(t.cache) >= maxPatternCache {
+		clear(t.cache)
+	}
+	t.cache[key] = cbs
+	return cbs
+}
+
+func (t *PatternEventTable) resolve(key string) []CB {
+	exact := t.exact.CBList(key)
+	nodes := t.root.match(strings.Split(key, PatternSep), nil)
+	if len(nodes) == 0 {
+		return exact
+	}
+	cbs := make([]CB, len(exact), len(exact)+len(nodes))
+	copy(cbs, exact)
+	for _, n := range nodes {
+		for _, pcb := range n.cbs {
+			cbs = append(cbs, func(event any) { pcb(key, event) })
+		}
+	}
+	return cbs
+}
@@ -0,0 +1,75 @@
+package trigger
+
+import (
+	"github.com/hyicode/utils/assert"
+	"testing"
+)
+
+type orderEvent struct {
+	ID int
+}
+
+func TestPatternEventTable(t *testing.T) {
+	const (
+		Created  EventName[orderEvent] = "order.created"
+		Paid     EventName[orderEvent] = "order.paid"
+		Added    EventName[string]     = "order.item.added"
+		Order    EventName[int]        = "order"
+		Shipment EventName[int]        = "shipment.sent"
+	)
+	table := NewPatternEventTable()
+
+	var one, every, all, typed, exact []string
+	EventPattern[any]("order.*").On(table, func(key string, event any) {
+		one = append(one, key)
+	})
+	EventPattern[any]("**").On(table, func(key string, event any) {
+		every = append(every, key)
+	})
+	EventPattern[any]("order.**").On(table, func(key string, event any) {
+		all = append(all, key)
+	})
+	EventPattern[orderEvent]("*.*").On(table, func(key string, event orderEvent) {
+		assert.EqualErrorf(t, 7, event.ID, "typed payload")
+		typed = append(typed, key)
+	})
+	Paid.On(table, func(event orderEvent) {
+		exact = append(exact, string(Paid))
+	})
+
+	Created.Trigger(table, orderEvent{ID: 7})
+	Paid.Trigger(table, orderEvent{ID: 7})
+	Added.Trigger(table, "sku")
+	Order.Trigger(table, 1)
+	Shipment.Trigger(table, 2)
+
+	expectKeys(t, "order.*", []string{"order.created", "order.paid"}, one)
+	expectKeys(t, "**", []string{"order.created", "order.paid", "order.item.added", "order", "shipment.sent"}, every)
+	expectKeys(t, "order.**", []string{"order.created", "order.paid", "order.item.added", "order"}, all)
+	expectKeys(t, "*.*", []string{"order.created", "order.paid"}, typed)
+	expectKeys(t, "exact", []string{"order.paid"}, exact)
+}
+
+func TestPatternEventTableRegisterAfterTrigger(t *testing.T) {
+	const Paid EventName[int] = "order.paid"
+	table := NewPatternEventTable()
+
+	count := 0
+	Paid.Trigger(table, 1)
+	EventName[int]("order.*").On(table, func(event int) {
+		count += event
+	})
+	Paid.Trigger(table, 2)
+	EventPattern[int]("order.**").On(table, func(key string, event int) {
+		count += event
+	})
+	Paid.Trigger(table, 3)
+	assert.EqualErrorf(t, 8, count, "count")
+}
+
+func expectKeys(t *testing.T, name string, expect, actual []string) {
+	assert.EqualFatalf(t, len(expect), len(actual), "%s len %v", name, actual)
+	for i := range expect {
+		assert.EqualErrorf(t, expect[i], actual[i], "%s", name)
+	}
+}
@@ -0,0 +1,84 @@
+package trigger
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+)
+
+// query is the payload passed to the handlers of a QueryName.
+type query[Req, Resp any] struct {
+	req   Req
+	resps []Resp
+}
+
+// QueryName is the request/reply counterpart of EventName: its handlers
+// answer a Req with a Resp and the caller collects the answers. Queries run
+// synchronously on the caller's goroutine through CBList, also on tables
+// that dispatch events asynchronously.
+type QueryName[Req, Resp any] string
+
+func (q QueryName[Req, Resp]) On(t EventTableI, cb func(req Req) Resp) error {
+	key := string(q)
+	if err := checkOn[*query[Req, Resp]](t, key); err != nil {
+		return err
+	}
+	t.RegisterCB(key, func(event any) {
+		c, ok := event.(*query[Req, Resp])
+		if !ok {
+			panic(fmt.Sprintf("trigger: query handler of %q expects %v, got %T", key, reflect.TypeFor[*query[Req, Resp]](), event))
+		}
+		c.resps = append(c.resps, cb(c.req))
+	})
+	return nil
+}
+
+// Ask returns the responses of every handler in registration order.
+func (q QueryName[Req, Resp]) Ask(t EventTableI, req Req) ([]Resp, error) {
+	return q.ask(t, req, 0)
+}
+
+// First returns the response of the first handler only, the others are not
+// called. ok is false when no handler is registered.
+func (q QueryName[Req, Resp]) First(t EventTableI, req Req) (resp Resp, ok bool, err error) {
+	resps, err := q.ask(t, req, 1)
+	if err != nil || len(resps) == 0 {
+		return resp, false, err
+	}
+	return resps[0], true, nil
+}
+
+// Reduce folds the responses with f, e.g. Reduce(t, req, MinResp). ok is
+// false when no handler is registered.
+func (q QueryName[Req, Resp]) Reduce(t EventTableI, req Req, f func(acc, resp Resp) Resp) (acc Resp, ok bool, err error) {
+	resps, err := q.ask(t, req, 0)
+	if err != nil || len(resps) == 0 {
+		return acc, false, err
+	}
+	acc = resps[0]
+	for _, resp := range resps[1:] {
+		acc = f(acc, resp)
+	}
+	return acc, true, nil
+}
+
+func (q QueryName[Req, Resp]) ask(t EventTableI, req Req, limit int) ([]Resp, error) {
+	key := string(q)
+	if err := checkType[*query[Req, Resp]](t, key); err != nil {
+		return nil, err
+	}
+	c := &query[Req, Resp]{req: req}
+	for _, cb := range t.CBList(key) {
+		cb(c)
+		if limit > 0 && len(c.resps) >= limit {
+			break
+		}
+	}
+	return c.resps, nil
+}
+
+// MinResp and MaxResp are reducers keeping the lowest and the highest
+// response.
+func MinResp[T cmp.Ordered](a, b T) T { return min(a, b) }
+
+func MaxResp[T cmp.Ordered](a, b T) T { return max(a, b) }
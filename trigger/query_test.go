@@ -0,0 +1,69 @@
+package trigger
+
+import (
+	"errors"
+	"github.com/hyicode/utils/assert"
+	"testing"
+)
+
+func TestQueryName(t *testing.T) {
+	const Quote QueryName[string, float64] = "quote"
+	tables := map[string]EventTableI{
+		"EventTable":      NewEventTable(),
+		"EventTableMutex": NewEventTableMutex(),
+		"AsyncEventTable": NewAsyncEventTable(nil, AsyncConfig{Workers: 1}),
+	}
+	for name, table := range tables {
+		_, ok, err := Quote.First(table, "apple")
+		assert.EqualFatalf(t, false, ok, "%s first without handler", name)
+		assert.EqualFatalf(t, nil, err, "%s first without handler", name)
+
+		calls := 0
+		for _, price := range []float64{3, 1, 2} {
+			Quote.On(table, func(item string) float64 {
+				calls++
+				assert.EqualErrorf(t, "apple", item, "%s request", name)
+				return price
+			})
+		}
+
+		resps, err := Quote.Ask(table, "apple")
+		assert.EqualFatalf(t, nil, err, "%s ask", name)
+		assert.EqualFatalf(t, 3, len(resps), "%s ask", name)
+		for i, v := range []float64{3, 1, 2} {
+			assert.EqualErrorf(t, v, resps[i], "%s ask", name)
+		}
+
+		calls = 0
+		first, ok, _ := Quote.First(table, "apple")
+		assert.EqualErrorf(t, true, ok, "%s first", name)
+		assert.EqualErrorf(t, 3.0, first, "%s first", name)
+		assert.EqualErrorf(t, 1, calls, "%s first calls", name)
+
+		low, _, _ := Quote.Reduce(table, "apple", MinResp[float64])
+		assert.EqualErrorf(t, 1.0, low, "%s min", name)
+		high, _, _ := Quote.Reduce(table, "apple", MaxResp[float64])
+		assert.EqualErrorf(t, 3.0, high, "%s max", name)
+		sum, _, _ := Quote.Reduce(table, "apple", func(acc, resp float64) float64 { return acc + resp })
+		assert.EqualErrorf(t, 6.0, sum, "%s sum", name)
+	}
+	tables["AsyncEventTable"].(*AsyncEventTable).Close()
+}
+
+func TestQueryNameTypeMismatch(t *testing.T) {
+	table := NewTypedEventTable(NewEventTable(), false)
+	EventName[string]("quote").On(table, func(event string) {})
+
+	var mismatch *TypeMismatchError
+	err := QueryName[string, int]("quote").On(table, func(req string) int { return 0 })
+	assert.EqualErrorf(t, true, errors.As(err, &mismatch), "on: %v", err)
+	_, err = QueryName[string, int]("quote").Ask(table, "x")
+	assert.EqualErrorf(t, true, errors.As(err, &mismatch), "ask: %v", err)
+}
+
+func TestQueryNameHandlerMismatch(t *testing.T) {
+	table := NewEventTable()
+	QueryName[string, int]("quote").On(table, func(req string) int { return 0 })
+	assert.PanicsWithErrorf(t, `trigger: query handler of "quote" expects *trigger.query[string,int], got *trigger.query[string,float64]`,
+		func() { QueryName[string, float64]("quote").Ask(table, "x") }, "untyped table")
+}
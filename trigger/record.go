@@ -0,0 +1,182 @@
+package trigger
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/hyicode/utils/clock"
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Record is one triggered event, written as a line of JSON.
+type Record struct {
+	Key     string          `json:"key"`
+	Payload json.RawMessage `json:"payload"`
+	Time    time.Time       `json:"time"`
+}
+
+// Registry maps keys back to payload types, so that JSON-encoded payloads
+// can be decoded into the T their EventName was declared with.
+type Registry struct {
+	mu     sync.RWMutex
+	decode map[string]func(data []byte) (any, error)
+}
+
+func NewRegistry() *Registry {
+	return &Registry{decode: make(map[string]func(data []byte) (any, error))}
+}
+
+// Register lets r decode the payloads of e.
+func (e EventName[T]) Register(r *Registry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.decode[string(e)] = func(data []byte) (any, error) {
+		var v T
+		err := json.Unmarshal(data, &v)
+		return v, err
+	}
+}
+
+// Decode decodes data into the payload type registered for key.
+func (r *Registry) Decode(key string, data []byte) (any, error) {
+	r.mu.RLock()
+	decode, ok := r.decode[key]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("trigger: event %q is not registered", key)
+	}
+	v, err := decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("trigger: decode %q: %w", key, err)
+	}
+	return v, nil
+}
+
+// Has reports whether key is registered.
+func (r *Registry) Has(key string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.decode[key]
+	return ok
+}
+
+// Keys returns the registered keys, sorted.
+func (r *Registry) Keys() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	keys := make([]string, 0, len(r.decode))
+	for k := range r.decode {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// RecordingEventTable writes a Record of every event triggered on it to a
+// JSON Lines stream before dispatching the event to table.
+type RecordingEventTable struct {
+	table EventTableI
+	clock clock.Clock
+
+	mu  sync.Mutex
+	enc *json.Encoder
+	err error
+}
+
+// NewRecordingEventTable records to w, timestamps are taken from clk, nil
+// meaning clock.Real.
+func NewRecordingEventTable(table EventTableI, w io.Writer, clk clock.Clock) *RecordingEventTable {
+	return &RecordingEventTable{table: table, clock: orRealClock(clk), enc: json.NewEncoder(w)}
+}
+
+func (t *RecordingEventTable) RegisterCB(key string, cb CB) {
+	t.table.RegisterCB(key, cb)
+}
+
+func (t *RecordingEventTable) RegisterRemovableCB(key string, cb CB) (remove func()) {
+	return RegisterRemovable(t.table, key, cb)
+}
+
+func (t *RecordingEventTable) CBList(key string) []CB {
+	return t.table.CBList(key)
+}
+
+// Dispatch records event and dispatches it. The event is dispatched even if
+// it can't be recorded, the recording error is returned afterwards.
+func (t *RecordingEventTable) Dispatch(key string, event any) error {
+	err := t.record(key, event)
+	return errors.Join(err, Dispatch(t.table, key, event))
+}
+
+// Err returns the first error met while recording.
+func (t *RecordingEventTable) Err() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.err
+}
+
+func (t *RecordingEventTable) record(key string, event any) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		err = fmt.Errorf("trigger: record %q: %w", key, err)
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err == nil {
+		// records are written under the lock so their order matches their time
+		err = t.enc.Encode(Record{Key: key, Payload: payload, Time: t.clock.Now()})
+	}
+	if err != nil && t.err == nil {
+		t.err = err
+	}
+	return err
+}
+
+type ReplayConfig struct {
+	Registry *Registry
+	// Realtime waits between events as long as they were apart when they
+	// were recorded, instead of replaying as fast as possible.
+	Realtime bool
+	Clock    clock.Clock // used to wait in realtime mode, defaults to clock.Real
+}
+
+// Replay reads a stream written by a RecordingEventTable and triggers its
+// events on t. It returns the number of events triggered, stopping at the
+// first event that can't be decoded or dispatched.
+func Replay(ctx context.Context, r io.Reader, t EventTableI, cfg ReplayConfig) (int, error) {
+	if cfg.Registry == nil {
+		return 0, errors.New("trigger: replay needs a Registry")
+	}
+	clk := orRealClock(cfg.Clock)
+	dec := json.NewDecoder(r)
+	var prev time.Time
+	for n := 0; ; n++ {
+		var rec Record
+		if err := dec.Decode(&rec); err != nil {
+			if errors.Is(err, io.EOF) {
+				return n, nil
+			}
+			return n, fmt.Errorf("trigger: replay record %d: %w", n, err)
+		}
+		event, err := cfg.Registry.Decode(rec.Key, rec.Payload)
+		if err != nil {
+			return n, err
+		}
+		if cfg.Realtime && n > 0 {
+			if err := clk.SleepContext(ctx, rec.Time.Sub(prev)); err != nil {
+				return n, err
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return n, err
+		}
+		prev = rec.Time
+		if err := Dispatch(t, rec.Key, event); err != nil {
+			return n, err
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package trigger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/hyicode/utils/assert"
+	"github.com/hyicode/utils/clock"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecordReplay(t *testing.T) {
+	const (
+		Created EventName[orderEvent] = "order.created"
+		Note    EventName[string]     = "order.note"
+	)
+	start := time.Unix(100, 0)
+	clk := clock.NewFake(start)
+	var buf bytes.Buffer
+	recording := NewRecordingEventTable(NewEventTable(), &buf, clk)
+
+	Created.Trigger(recording, orderEvent{ID: 1})
+	clk.Advance(time.Second)
+	Note.Trigger(recording, "fragile")
+	clk.Advance(2 * time.Second)
+	Created.Trigger(recording, orderEvent{ID: 2})
+	assert.EqualFatalf(t, nil, recording.Err(), "record")
+	assert.EqualFatalf(t, 3, strings.Count(buf.String(), "\n"), "lines")
+
+	reg := NewRegistry()
+	Created.Register(reg)
+	Note.Register(reg)
+
+	for _, realtime := range []bool{false, true} {
+		replayClk := clock.NewFake(start)
+		table := NewEventTable()
+		var got []string
+		var at []time.Duration
+		Created.On(table, func(event orderEvent) {
+			got = append(got, "created")
+			at = append(at, replayClk.Now().Sub(start))
+			assert.EqualErrorf(t, len(got)/2+1, event.ID, "id")
+		})
+		Note.On(table, func(event string) {
+			got = append(got, event)
+			at = append(at, replayClk.Now().Sub(start))
+		})
+
+		n, err := Replay(context.Background(), bytes.NewReader(buf.Bytes()), table,
+			ReplayConfig{Registry: reg, Realtime: realtime, Clock: replayClk})
+		assert.EqualFatalf(t, nil, err, "replay")
+		assert.EqualFatalf(t, 3, n, "replayed")
+		assert.EqualFatalf(t, 3, len(got), "got %v", got)
+		assert.EqualErrorf(t, "fragile", got[1], "note")
+		if realtime {
+			assert.EqualErrorf(t, time.Second, at[1], "note delay")
+			assert.EqualErrorf(t, 3*time.Second, at[2], "created delay")
+		} else {
+			assert.EqualErrorf(t, time.Duration(0), at[2], "as fast as possible")
+		}
+	}
+}
+
+func TestReplayUnknownKey(t *testing.T) {
+	stream := `{"key":"a","payload":1,"time":"2024-01-01T00:00:00Z"}
+{"key":"b","payload":2,"time":"2024-01-01T00:00:00Z"}
+`
+	reg := NewRegistry()
+	EventName[int]("a").Register(reg)
+	count := 0
+	table := NewEventTable()
+	EventName[int]("a").On(table, func(event int) { count += event })
+
+	n, err := Replay(context.Background(), strings.NewReader(stream), table, ReplayConfig{Registry: reg})
+	assert.EqualErrorf(t, 1, n, "replayed")
+	assert.EqualErrorf(t, 1, count, "count")
+	assert.EqualErrorf(t, true, err != nil, "unknown key")
+}
+
+func TestReplayNoRegistry(t *testing.T) {
+	n, err := Replay(context.Background(), strings.NewReader(`{"key":"a","payload":1}`), NewEventTable(), ReplayConfig{})
+	assert.EqualErrorf(t, 0, n, "replayed")
+	assert.EqualErrorf(t, "trigger: replay needs a Registry", fmt.Sprint(err), "error")
+}
@@ -0,0 +1,247 @@
+package trigger
+
+import (
+	"github.com/hyicode/utils/clock"
+	"github.com/hyicode/utils/container"
+	"sync"
+	"time"
+)
+
+const (
+	wheelBits   = 6
+	wheelSlots  = 1 << wheelBits
+	wheelLevels = 4
+	// wheelSpan is the farthest a timer can be placed, later ones are
+	// parked in the last level and placed again when cascaded.
+	wheelSpan = 1 << (wheelBits * wheelLevels)
+)
+
+type SchedulerConfig struct {
+	Tick    time.Duration // resolution, defaults to 10ms
+	Clock   clock.Clock   // defaults to clock.Real
+	OnError func(key string, err error)
+}
+
+// Scheduler triggers events later on a table. Timers live in a hierarchical
+// timing wheel of wheelLevels levels of wheelSlots slots each, so adding and
+// stopping one is O(1) whatever the number of timers.
+type Scheduler struct {
+	table   EventTableI
+	clock   clock.Clock
+	tick    time.Duration
+	onError func(key string, err error)
+
+	mu      sync.Mutex
+	start   time.Time
+	now     uint64 // ticks since start the wheel has reached
+	wheels  [wheelLevels][wheelSlots]container.List[*Timer]
+	timers  int
+	ticker  clock.Timer
+	stopped bool
+}
+
+// Timer is a scheduled trigger.
+type Timer struct {
+	key      string
+	fire     func() error
+	expire   uint64 // tick
+	interval uint64 // ticks between firings, 0 for one-shot timers
+	list     *container.List[*Timer]
+	elem     *container.Element[*Timer]
+	s        *Scheduler
+}
+
+func NewScheduler(table EventTableI, cfg SchedulerConfig) *Scheduler {
+	if cfg.Tick <= 0 {
+		cfg.Tick = 10 * time.Millisecond
+	}
+	if cfg.Clock == nil {
+		cfg.Clock = clock.Real
+	}
+	return &Scheduler{
+		table:   table,
+		clock:   cfg.Clock,
+		tick:    cfg.Tick,
+		onError: cfg.OnError,
+		start:   cfg.Clock.Now(),
+	}
+}
+
+// TriggerAfter triggers event on the scheduler's table once d has passed.
+func (e EventName[T]) TriggerAfter(s *Scheduler, d time.Duration, event T) *Timer {
+	return e.TriggerAt(s, s.clock.Now().Add(d), event)
+}
+
+// TriggerAt triggers event on the scheduler's table at the first tick not
+// before at.
+func (e EventName[T]) TriggerAt(s *Scheduler, at time.Time, event T) *Timer {
+	return s.schedule(string(e), s.ticks(at), 0, func() error {
+		return e.Trigger(s.table, event)
+	})
+}
+
+// Every triggers event on the scheduler's table every interval, starting one
+// interval from now, until the timer is stopped.
+func (e EventName[T]) Every(s *Scheduler, interval time.Duration, event T) *Timer {
+	n := uint64((interval + s.tick - 1) / s.tick)
+	if n == 0 {
+		n = 1
+	}
+	return s.schedule(string(e), s.ticks(s.clock.Now())+n, n, func() error {
+		return e.Trigger(s.table, event)
+	})
+}
+
+// Stop reports whether the call prevented the timer from firing again.
+func (t *Timer) Stop() bool {
+	t.s.mu.Lock()
+	defer t.s.mu.Unlock()
+	if t.list == nil {
+		return false
+	}
+	t.s.unlink(t)
+	t.interval = 0
+	return true
+}
+
+// Len returns the number of pending timers.
+func (s *Scheduler) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.timers
+}
+
+// Stop drops every pending timer.
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stopped = true
+	if s.ticker != nil {
+		s.ticker.Stop()
+		s.ticker = nil
+	}
+	for level := range s.wheels {
+		for slot := range s.wheels[level] {
+			list := &s.wheels[level][slot]
+			for e := list.Front(); e != nil; e = e.Next() {
+				// a dropped timer's Stop reports false
+				e.Value.list, e.Value.elem, e.Value.interval = nil, nil, 0
+			}
+			list.Init()
+		}
+	}
+	s.timers = 0
+}
+
+// ticks converts at to the first tick not before it.
+func (s *Scheduler) ticks(at time.Time) uint64 {
+	d := at.Sub(s.start)
+	if d <= 0 {
+		return 0
+	}
+	return uint64((d + s.tick - 1) / s.tick)
+}
+
+// elapsed returns the number of whole ticks between start and now.
+func (s *Scheduler) elapsed(now time.Time) uint64 {
+	d := now.Sub(s.start)
+	if d <= 0 {
+		return 0
+	}
+	return uint64(d / s.tick)
+}
+
+func (s *Scheduler) schedule(key string, expire, interval uint64, fire func() error) *Timer {
+	t := &Timer{key: key, fire: fire, expire: expire, interval: interval, s: s}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped {
+		return t
+	}
+	if s.timers == 0 {
+		// nothing to cascade while idle, catch up with the clock at once
+		s.now = max(s.now, s.elapsed(s.clock.Now()))
+	}
+	t.expire = max(t.expire, s.now+1)
+	s.link(t)
+	if s.ticker == nil {
+		s.ticker = s.clock.AfterFunc(s.tick, s.run)
+	}
+	return t
+}
+
+// link places t in the wheel, t.expire must not be before the current tick.
+// s.mu must be held.
+func (s *Scheduler) link(t *Timer) {
+	expire := t.expire
+	delta := expire - s.now
+	level := 0
+	for level < wheelLevels-1 && delta >= 1<<(wheelBits*(level+1)) {
+		level++
+	}
+	if delta >= wheelSpan {
+		expire = s.now + wheelSpan - 1
+	}
+	t.list = &s.wheels[level][(expire>>(wheelBits*level))&(wheelSlots-1)]
+	t.elem = t.list.PushBack(t)
+	s.timers++
+}
+
+// unlink removes t from the wheel. s.mu must be held.
+func (s *Scheduler) unlink(t *Timer) {
+	t.list.Remove(t.elem)
+	t.list, t.elem = nil, nil
+	s.timers--
+}
+
+// step advances the wheel by one tick and appends the timers due to due.
+// s.mu must be held.
+func (s *Scheduler) step(due []*Timer) []*Timer {
+	s.now++
+	for level := 1; level < wheelLevels; level++ {
+		if s.now&(1<<(wheelBits*level)-1) != 0 {
+			break
+		}
+		list := &s.wheels[level][(s.now>>(wheelBits*level))&(wheelSlots-1)]
+		for e := list.Front(); e != nil; e = list.Front() {
+			t := e.Value
+			s.unlink(t)
+			s.link(t)
+		}
+	}
+
+	list := &s.wheels[0][s.now&(wheelSlots-1)]
+	for e := list.Front(); e != nil; e = list.Front() {
+		t := e.Value
+		s.unlink(t)
+		if t.interval > 0 {
+			t.expire = s.now + t.interval
+			s.link(t)
+		}
+		due = append(due, t)
+	}
+	return due
+}
+
+func (s *Scheduler) run() {
+	s.mu.Lock()
+	if s.stopped {
+		s.mu.Unlock()
+		return
+	}
+	var due []*Timer
+	for target := s.elapsed(s.clock.Now()); s.now < target && s.timers > 0; {
+		due = s.step(due)
+	}
+	s.ticker = nil
+	if s.timers > 0 {
+		s.ticker = s.clock.AfterFunc(s.tick, s.run)
+	}
+	s.mu.Unlock()
+
+	for _, t := range due {
+		if err := t.fire(); err != nil && s.onError != nil {
+			s.onError(t.key, err)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package trigger
+
+import (
+	"github.com/hyicode/utils/assert"
+	"github.com/hyicode/utils/clock"
+	"testing"
+	"time"
+)
+
+func TestSchedulerTriggerAfter(t *testing.T) {
+	const key EventName[string] = "later"
+	clk := clock.NewFake(time.Unix(0, 0))
+	table := NewEventTable()
+	s := NewScheduler(table, SchedulerConfig{Tick: 10 * time.Millisecond, Clock: clk})
+
+	var fired []time.Time
+	key.On(table, func(event string) {
+		assert.EqualErrorf(t, "hello", event, "payload")
+		fired = append(fired, clk.Now())
+	})
+	key.TriggerAfter(s, 25*time.Millisecond, "hello")
+	stopped := key.TriggerAfter(s, 25*time.Millisecond, "stopped")
+	assert.EqualFatalf(t, 2, s.Len(), "len")
+	assert.EqualFatalf(t, true, stopped.Stop(), "stop")
+	assert.EqualFatalf(t, false, stopped.Stop(), "stop twice")
+
+	clk.Advance(20 * time.Millisecond)
+	assert.EqualFatalf(t, 0, len(fired), "fired early")
+	clk.Advance(10 * time.Millisecond)
+	assert.EqualFatalf(t, 1, len(fired), "fired")
+	assert.EqualErrorf(t, time.Unix(0, 0).Add(30*time.Millisecond), fired[0], "fired at")
+	assert.EqualErrorf(t, 0, s.Len(), "len after fire")
+}
+
+func TestSchedulerWheelLevels(t *testing.T) {
+	const key EventName[time.Duration] = "levels"
+	const tick = time.Millisecond
+	start := time.Unix(0, 0)
+	clk := clock.NewFake(start)
+	table := NewEventTable()
+	s := NewScheduler(table, SchedulerConfig{Tick: tick, Clock: clk})
+
+	delays := []time.Duration{1, 63, 64, 65, 100, 4095, 4096, 4097, 20000, 262143, 262144, 262145, 300000}
+	fired := 0
+	key.On(table, func(delay time.Duration) {
+		at := clk.Now().Sub(start)
+		assert.EqualErrorf(t, delay, at, "fired at")
+		fired++
+	})
+	for _, d := range delays {
+		key.TriggerAt(s, start.Add(d*tick), d*tick)
+	}
+	clk.Advance(64 * tick)
+	// a timer added mid-way through the wheel
+	key.TriggerAfter(s, 5000*tick, 5064*tick)
+
+	clk.Advance(400000 * tick)
+	assert.EqualErrorf(t, len(delays)+1, fired, "fired")
+	assert.EqualErrorf(t, 0, s.Len(), "len")
+}
+
+func TestSchedulerEvery(t *testing.T) {
+	const key EventName[int] = "every"
+	clk := clock.NewFake(time.Unix(0, 0))
+	table := NewEventTable()
+	s := NewScheduler(table, SchedulerConfig{Clock: clk})
+
+	count := 0
+	key.On(table, func(event int) {
+		count += event
+		if count == 5 {
+			key.TriggerAfter(s, 50*time.Millisecond, 100)
+		}
+	})
+	timer := key.Every(s, 100*time.Millisecond, 1)
+
+	clk.Advance(time.Second)
+	assert.EqualFatalf(t, 110, count, "count")
+	assert.EqualFatalf(t, true, timer.Stop(), "stop")
+	clk.Advance(time.Second)
+	assert.EqualFatalf(t, 110, count, "count after stop")
+	assert.EqualErrorf(t, 0, s.Len(), "len")
+	assert.EqualErrorf(t, 0, clk.Pending(), "idle scheduler keeps no clock timer")
+}
+
+func TestSchedulerStop(t *testing.T) {
+	const key EventName[int] = "stop"
+	clk := clock.NewFake(time.Unix(0, 0))
+	table := NewEventTable()
+	s := NewScheduler(table, SchedulerConfig{Clock: clk})
+	fired := false
+	key.On(table, func(event int) { fired = true })
+	t1 := key.TriggerAfter(s, time.Second, 1)
+	t2 := key.Every(s, time.Minute, 2)
+	s.Stop()
+	assert.EqualErrorf(t, false, t1.Stop(), "stop dropped timer")
+	assert.EqualErrorf(t, false, t2.Stop(), "stop dropped ticker")
+	assert.EqualErrorf(t, 0, s.Len(), "len after stop")
+	clk.Advance(time.Hour)
+	assert.EqualErrorf(t, false, fired, "fired after stop")
+}
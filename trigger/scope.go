@@ -0,0 +1,196 @@
+package trigger
+
+import (
+	"errors"
+	"sync"
+)
+
+// ScopeSep separates the names of the path of a ScopedEventTable.
+const ScopeSep = "/"
+
+// OriginCB is a handler that also receives the path of the table the event
+// was triggered on.
+type OriginCB func(origin string, event any)
+
+type originEvent struct {
+	origin string
+	event  any
+}
+
+// ScopedEventTable is a concurrency-safe table belonging to a tree of tables,
+// one per sub-system (scene, room, session...). Events triggered on a table
+// are delivered to its own handlers, then to the handlers of its ancestors
+// up to the first boundary table. OnOrigin handlers also learn which table
+// the event came from.
+//
+// Children only keep a reference to their parent, never the other way round,
+// so a closed child can be collected.
+type ScopedEventTable struct {
+	name     string
+	path     string
+	parent   *ScopedEventTable
+	boundary bool
+	table    EventTableI
+	origin   EventTableI // handlers taking an originEvent
+
+	mu      sync.Mutex
+	closed  bool
+	removes map[uint64]func() // registrations made on the parent through Parent()
+	lastID  uint64
+}
+
+func NewScopedEventTable(name string) *ScopedEventTable {
+	return &ScopedEventTable{
+		name:   name,
+		path:   name,
+		table:  NewEventTableMutex(),
+		origin: NewEventTableMutex(),
+	}
+}
+
+// NewChild returns a table forwarding its events to t. A boundary child
+// keeps its events, and the ones of its own children, from reaching t.
+func (t *ScopedEventTable) NewChild(name string, boundary bool) *ScopedEventTable {
+	c := NewScopedEventTable(name)
+	c.path = t.path + ScopeSep + name
+	c.parent = t
+	c.boundary = boundary
+	return c
+}
+
+func (t *ScopedEventTable) Name() string { return t.name }
+
+// Path returns the names from the root down to t joined by ScopeSep.
+func (t *ScopedEventTable) Path() string { return t.path }
+
+func (t *ScopedEventTable) RegisterCB(key string, cb CB) {
+	t.table.RegisterCB(key, cb)
+}
+
+func (t *ScopedEventTable) RegisterRemovableCB(key string, cb CB) (remove func()) {
+	return RegisterRemovable(t.table, key, cb)
+}
+
+func (t *ScopedEventTable) CBList(key string) []CB {
+	return t.table.CBList(key)
+}
+
+// RegisterOriginCB registers cb for key, it is called with the path of the
+// table each event was triggered on.
+func (t *ScopedEventTable) RegisterOriginCB(key string, cb OriginCB) (remove func()) {
+	return RegisterRemovable(t.origin, key, func(event any) {
+		e := event.(originEvent)
+		cb(e.origin, e.event)
+	})
+}
+
+// OnOrigin is On for handlers that want to know which table of the tree the
+// event was triggered on.
+func (e EventName[T]) OnOrigin(t *ScopedEventTable, cb func(origin string, event T)) (remove func(), err error) {
+	key := string(e)
+	if err := checkOn[T](t, key); err != nil {
+		return nil, err
+	}
+	return t.RegisterOriginCB(key, func(origin string, event any) {
+		cb(origin, argOf[T](key, event))
+	}), nil
+}
+
+// Dispatch delivers event on t and its ancestors. It returns ErrTableClosed
+// once t is closed; a closed ancestor stops the propagation.
+func (t *ScopedEventTable) Dispatch(key string, event any) error {
+	var errs []error
+	for cur := t; cur != nil; cur = cur.parent {
+		if cur.isClosed() {
+			if cur == t {
+				return ErrTableClosed
+			}
+			break
+		}
+		if err := Dispatch(cur.table, key, event); err != nil {
+			errs = append(errs, err)
+		}
+		if cbs := cur.origin.CBList(key); len(cbs) > 0 {
+			oe := originEvent{origin: t.path, event: event}
+			for _, cb := range cbs {
+				cb(oe)
+			}
+		}
+		if cur.boundary {
+			break
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Parent returns a view of the parent table whose registrations are
+// removed when t is closed, or nil for a root table.
+func (t *ScopedEventTable) Parent() EventTableI {
+	if t.parent == nil {
+		return nil
+	}
+	return &scopedParent{child: t}
+}
+
+// Close stops t from delivering and forwarding events and removes the
+// handlers registered through Parent().
+func (t *ScopedEventTable) Close() {
+	t.mu.Lock()
+	t.closed = true
+	removes := t.removes
+	t.removes = nil
+	t.mu.Unlock()
+	for _, remove := range removes {
+		remove()
+	}
+}
+
+func (t *ScopedEventTable) isClosed() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.closed
+}
+
+// track keeps remove for Close until it runs, or runs it at once if t is
+// already closed.
+func (t *ScopedEventTable) track(remove func()) func() {
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		remove()
+		return func() {}
+	}
+	if t.removes == nil {
+		t.removes = make(map[uint64]func())
+	}
+	t.lastID++
+	id := t.lastID
+	t.removes[id] = remove
+	t.mu.Unlock()
+	return func() {
+		t.mu.Lock()
+		delete(t.removes, id)
+		t.mu.Unlock()
+		remove()
+	}
+}
+
+type scopedParent struct {
+	child *ScopedEventTable
+}
+
+func (p *scopedParent) RegisterCB(key string, cb CB) {
+	p.RegisterRemovableCB(key, cb)
+}
+
+func (p *scopedParent) RegisterRemovableCB(key string, cb CB) (remove func()) {
+	return p.child.track(p.child.parent.RegisterRemovableCB(key, cb))
+}
+
+func (p *scopedParent) CBList(key string) []CB {
+	return p.child.parent.CBList(key)
+}
+
+func (p *scopedParent) Dispatch(key string, event any) error {
+	return p.child.parent.Dispatch(key, event)
+}
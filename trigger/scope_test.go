@@ -0,0 +1,62 @@
+package trigger
+
+import (
+	"github.com/hyicode/utils/assert"
+	"testing"
+)
+
+func TestScopedEventTable(t *testing.T) {
+	const Joined EventName[string] = "joined"
+	root := NewScopedEventTable("game")
+	scene := root.NewChild("scene1", false)
+	room := scene.NewChild("room7", false)
+	private := scene.NewChild("private", true)
+	lobby := private.NewChild("lobby", false)
+
+	var rootGot, sceneGot []string
+	Joined.OnOrigin(root, func(origin string, event string) {
+		rootGot = append(rootGot, origin+":"+event)
+	})
+	Joined.On(scene, func(event string) {
+		sceneGot = append(sceneGot, event)
+	})
+	roomGot := 0
+	Joined.On(room, func(event string) { roomGot++ })
+
+	Joined.Trigger(room, "alice")
+	Joined.Trigger(scene, "bob")
+	Joined.Trigger(root, "carol")
+	Joined.Trigger(lobby, "dave")
+
+	expectKeys(t, "root", []string{"game/scene1/room7:alice", "game/scene1:bob", "game:carol"}, rootGot)
+	expectKeys(t, "scene", []string{"alice", "bob"}, sceneGot)
+	assert.EqualErrorf(t, 1, roomGot, "room")
+	assert.EqualErrorf(t, "game/scene1/private/lobby", lobby.Path(), "path")
+}
+
+func TestScopedEventTableClose(t *testing.T) {
+	const Tick EventName[int] = "tick"
+	root := NewScopedEventTable("game")
+	room := root.NewChild("room", false)
+
+	parentTicks := 0
+	Tick.On(room.Parent(), func(event int) { parentTicks++ })
+	remove, _ := Tick.OnRemovable(room.Parent(), func(event int) { parentTicks += 100 })
+	rootTicks := 0
+	Tick.On(root, func(event int) { rootTicks++ })
+
+	Tick.Trigger(root, 1)
+	remove()
+	Tick.Trigger(root, 1)
+	assert.EqualFatalf(t, 102, parentTicks, "parent handlers")
+	assert.EqualFatalf(t, 2, len(root.CBList(string(Tick))), "registered on root")
+	assert.EqualFatalf(t, 1, len(room.removes), "removed registration forgotten")
+
+	room.Close()
+	assert.EqualFatalf(t, 1, len(root.CBList(string(Tick))), "parent handlers released")
+	assert.EqualErrorf(t, ErrTableClosed, Tick.Trigger(room, 1), "trigger on closed")
+	Tick.On(room.Parent(), func(event int) { parentTicks++ })
+	Tick.Trigger(root, 1)
+	assert.EqualErrorf(t, 102, parentTicks, "no handler left after close")
+	assert.EqualErrorf(t, 3, rootTicks, "root")
+}
@@ -0,0 +1,105 @@
+package trigger
+
+import "sync"
+
+// StickyEventTable keeps the last payloads of the keys it is told to retain
+// and replays them to handlers registered later, so that late subscribers
+// start from the current state. Replayed payloads are delivered on the
+// registering goroutine before RegisterCB returns.
+//
+// A payload triggered concurrently with a registration may reach the new
+// handler twice, once replayed and once dispatched.
+type StickyEventTable struct {
+	table EventTableI
+
+	mu     sync.Mutex
+	retain map[string]int
+	values map[string][]any // oldest first
+}
+
+func NewStickyEventTable(table EventTableI) *StickyEventTable {
+	return &StickyEventTable{
+		table:  table,
+		retain: make(map[string]int),
+		values: make(map[string][]any),
+	}
+}
+
+// Retain keeps the last n payloads of key. n <= 0 stops retaining key and
+// drops what was kept.
+func (t *StickyEventTable) Retain(key string, n int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if n <= 0 {
+		delete(t.retain, key)
+		delete(t.values, key)
+		return
+	}
+	t.retain[key] = n
+	if vals := t.values[key]; len(vals) > n {
+		t.values[key] = append([]any(nil), vals[len(vals)-n:]...)
+	}
+}
+
+// Clear drops the payloads retained for key, it stays sticky.
+func (t *StickyEventTable) Clear(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.values, key)
+}
+
+// Retained returns the payloads retained for key, oldest first.
+func (t *StickyEventTable) Retained(key string) []any {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]any(nil), t.values[key]...)
+}
+
+func (t *StickyEventTable) RegisterCB(key string, cb CB) {
+	t.mu.Lock()
+	t.table.RegisterCB(key, cb)
+	vals := t.values[key]
+	t.mu.Unlock()
+	for _, v := range vals {
+		cb(v)
+	}
+}
+
+func (t *StickyEventTable) RegisterRemovableCB(key string, cb CB) (remove func()) {
+	t.mu.Lock()
+	remove = RegisterRemovable(t.table, key, cb)
+	vals := t.values[key]
+	t.mu.Unlock()
+	for _, v := range vals {
+		cb(v)
+	}
+	return remove
+}
+
+func (t *StickyEventTable) CBList(key string) []CB {
+	return t.table.CBList(key)
+}
+
+func (t *StickyEventTable) Dispatch(key string, event any) error {
+	t.mu.Lock()
+	if n, ok := t.retain[key]; ok {
+		vals := t.values[key]
+		if len(vals) >= n {
+			// copy rather than shift, replays may still be reading vals
+			vals = append([]any(nil), vals[len(vals)-n+1:]...)
+		}
+		t.values[key] = append(vals, event)
+	}
+	t.mu.Unlock()
+	return Dispatch(t.table, key, event)
+}
+
+// Last returns the latest payload of e retained by t.
+func (e EventName[T]) Last(t *StickyEventTable) (last T, ok bool) {
+	vals := t.Retained(string(e))
+	if len(vals) == 0 {
+		return last, false
+	}
+	last, ok = vals[len(vals)-1].(T)
+	return last, ok
+}
@@ -0,0 +1,57 @@
+package trigger
+
+import (
+	"github.com/hyicode/utils/assert"
+	"testing"
+)
+
+func TestStickyEventTable(t *testing.T) {
+	const (
+		State   EventName[string] = "state"
+		History EventName[int]    = "history"
+		Plain   EventName[int]    = "plain"
+	)
+	table := NewStickyEventTable(NewEventTable())
+	table.Retain(string(State), 1)
+	table.Retain(string(History), 3)
+
+	State.Trigger(table, "loading")
+	State.Trigger(table, "ready")
+	for i := 1; i <= 5; i++ {
+		History.Trigger(table, i)
+	}
+	Plain.Trigger(table, 1)
+
+	var states []string
+	State.On(table, func(event string) { states = append(states, event) })
+	var history []int
+	History.On(table, func(event int) { history = append(history, event) })
+	plain := 0
+	Plain.On(table, func(event int) { plain++ })
+
+	assert.EqualFatalf(t, 1, len(states), "states %v", states)
+	assert.EqualErrorf(t, "ready", states[0], "replayed state")
+	expectInts(t, "history", []int{3, 4, 5}, history)
+	assert.EqualErrorf(t, 0, plain, "plain events are not retained")
+
+	State.Trigger(table, "done")
+	assert.EqualFatalf(t, 2, len(states), "states %v", states)
+	last, ok := State.Last(table)
+	assert.EqualErrorf(t, true, ok, "last")
+	assert.EqualErrorf(t, "done", last, "last")
+
+	table.Clear(string(State))
+	_, ok = State.Last(table)
+	assert.EqualErrorf(t, false, ok, "last after clear")
+	late := 0
+	State.On(table, func(event string) { late++ })
+	assert.EqualErrorf(t, 0, late, "nothing replayed after clear")
+	State.Trigger(table, "again")
+	last, _ = State.Last(table)
+	assert.EqualErrorf(t, "again", last, "still sticky after clear")
+
+	table.Retain(string(History), 1)
+	assert.EqualErrorf(t, 1, len(table.Retained(string(History))), "shrunk")
+	table.Retain(string(History), 0)
+	assert.EqualErrorf(t, 0, len(table.Retained(string(History))), "not retained")
+}
@@ -0,0 +1,113 @@
+package trigger
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Subscription adapts the events of an EventName into a buffered channel.
+type Subscription[T EventI] struct {
+	C <-chan T
+
+	ch      chan T
+	policy  OverflowPolicy
+	remove  func()
+	dropped atomic.Uint64
+
+	mu     sync.Mutex // serializes sends with closing ch
+	closed bool
+	done   chan struct{}
+	once   sync.Once
+}
+
+// Subscribe delivers the events of e into a channel buffering bufSize of
+// them, policy deciding what happens when the consumer falls behind. The
+// channel is closed by cancel. It fails with a *TypeMismatchError if e is
+// bound to another payload type on a TypeChecker table.
+func (e EventName[T]) Subscribe(t EventTableI, bufSize int, policy OverflowPolicy) (<-chan T, func(), error) {
+	s, err := e.NewSubscription(t, bufSize, policy)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.C, s.Cancel, nil
+}
+
+// NewSubscription is Subscribe returning the Subscription itself, which also
+// reports how many events were dropped. Policies other than Block need a
+// buffer, so bufSize is raised to 1 for them. CoalesceLatest ignores bufSize
+// and holds at most the newest event.
+func (e EventName[T]) NewSubscription(t EventTableI, bufSize int, policy OverflowPolicy) (*Subscription[T], error) {
+	if bufSize < 1 && policy != Block || policy == CoalesceLatest {
+		bufSize = 1
+	}
+	s := &Subscription[T]{
+		ch:     make(chan T, bufSize),
+		policy: policy,
+		done:   make(chan struct{}),
+	}
+	s.C = s.ch
+	remove, err := e.OnRemovable(t, s.send)
+	if err != nil {
+		return nil, err
+	}
+	s.remove = remove
+	return s, nil
+}
+
+// Dropped returns the number of events discarded by the overflow policy.
+func (s *Subscription[T]) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Cancel unregisters the subscription and closes C. Events still buffered
+// can be drained from C afterwards.
+func (s *Subscription[T]) Cancel() {
+	s.once.Do(func() {
+		s.remove()
+		close(s.done)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.closed = true
+		close(s.ch)
+	})
+}
+
+func (s *Subscription[T]) send(event T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	switch s.policy {
+	case DropNewest:
+		select {
+		case s.ch <- event:
+		default:
+			s.dropped.Add(1)
+		}
+	case DropOldest, CoalesceLatest:
+		for {
+			select {
+			case s.ch <- event:
+				return
+			default:
+			}
+			s.discard()
+		}
+	default:
+		select {
+		case s.ch <- event:
+		case <-s.done:
+		}
+	}
+}
+
+// discard drops the oldest buffered event, which with CoalesceLatest is the
+// pending one.
+func (s *Subscription[T]) discard() {
+	select {
+	case <-s.ch:
+		s.dropped.Add(1)
+	default:
+	}
+}
@@ -0,0 +1,150 @@
+package trigger
+
+import (
+	"errors"
+	"github.com/hyicode/utils/assert"
+	"testing"
+)
+
+func TestSubscribeOverflow(t *testing.T) {
+	const key EventName[int] = "sub"
+	tests := []struct {
+		name     string
+		policy   OverflowPolicy
+		events   int // 5 by default
+		received []int
+		dropped  uint64
+	}{
+		{name: "DropNewest", policy: DropNewest, received: []int{1, 2, 3}, dropped: 2},
+		{name: "DropOldest", policy: DropOldest, received: []int{3, 4, 5}, dropped: 2},
+		{name: "CoalesceLatest", policy: CoalesceLatest, received: []int{5}, dropped: 4},
+		{name: "CoalesceLatest4", policy: CoalesceLatest, events: 4, received: []int{4}, dropped: 3},
+	}
+	for _, tt := range tests {
+		table := NewEventTable()
+		s, err := key.NewSubscription(table, 3, tt.policy)
+		assert.EqualFatalf(t, nil, err, tt.name)
+		if tt.events == 0 {
+			tt.events = 5
+		}
+		for i := 1; i <= tt.events; i++ {
+			key.Trigger(table, i)
+		}
+		s.Cancel()
+
+		var received []int
+		for v := range s.C {
+			received = append(received, v)
+		}
+		assert.EqualFatalf(t, len(tt.received), len(received), "%s received %v", tt.name, received)
+		for i := range received {
+			assert.EqualErrorf(t, tt.received[i], received[i], tt.name)
+		}
+		assert.EqualErrorf(t, tt.dropped, s.Dropped(), "%s dropped", tt.name)
+	}
+}
+
+func TestSubscribeBlock(t *testing.T) {
+	const key EventName[int] = "sub"
+	table := NewEventTableMutex()
+	ch, cancel, err := key.Subscribe(table, 1, Block)
+	assert.EqualFatalf(t, nil, err, "subscribe")
+
+	go func() {
+		for i := 0; i < 100; i++ {
+			key.Trigger(table, i)
+		}
+		cancel()
+	}()
+	expect := 0
+	for v := range ch {
+		assert.EqualFatalf(t, expect, v, "order")
+		expect++
+	}
+	assert.EqualErrorf(t, 100, expect, "received")
+	assert.EqualErrorf(t, 0, len(table.CBList(string(key))), "handler removed")
+}
+
+func TestSubscribeCancelUnblocks(t *testing.T) {
+	const key EventName[int] = "sub"
+	table := NewEventTableMutex()
+	ch, cancel, err := key.Subscribe(table, 0, Block)
+	assert.EqualFatalf(t, nil, err, "subscribe")
+
+	done := make(chan struct{})
+	go func() {
+		key.Trigger(table, 1)
+		close(done)
+	}()
+	cancel()
+	<-done
+	for range ch {
+	}
+	cancel()
+	key.Trigger(table, 2)
+}
+
+func TestRegisterRemovable(t *testing.T) {
+	const key EventName[int] = "removable"
+	tables := map[string]EventTableI{
+		"EventTable":      NewEventTable(),
+		"EventTableMutex": NewEventTableMutex(),
+		"PatternTable":    NewPatternEventTable(),
+		"EventTableCOW":   NewEventTableCOW(),
+	}
+	for name, table := range tables {
+		var got []int
+		key.On(table, func(event int) { got = append(got, 1) })
+		remove2, _ := key.OnRemovable(table, func(event int) { got = append(got, 2) })
+		key.On(table, func(event int) { got = append(got, 3) })
+		remove4, _ := key.OnRemovable(table, func(event int) { got = append(got, 4) })
+
+		key.Trigger(table, 0)
+		remove2()
+		remove2()
+		key.Trigger(table, 0)
+		remove4()
+		key.Trigger(table, 0)
+
+		expect := []int{1, 2, 3, 4, 1, 3, 4, 1, 3}
+		assert.EqualFatalf(t, len(expect), len(got), "%s %v", name, got)
+		for i := range expect {
+			assert.EqualErrorf(t, expect[i], got[i], name)
+		}
+		assert.EqualErrorf(t, 2, len(table.CBList(string(key))), "%s: removed handlers dropped", name)
+	}
+}
+
+func TestPatternTableRemove(t *testing.T) {
+	table := NewPatternEventTable()
+	for i := 0; i < 3; i++ {
+		_, cancel, err := EventName[int]("a.b").Subscribe(table, 1, DropNewest)
+		assert.EqualFatalf(t, nil, err, "subscribe")
+		assert.EqualErrorf(t, 1, len(table.CBList("a.b")), "subscribed")
+		cancel()
+		assert.EqualErrorf(t, 0, len(table.CBList("a.b")), "cancelled")
+	}
+
+	var got []string
+	table.RegisterCB("a.*", func(event any) { got = append(got, "kept") })
+	remove := RegisterRemovable(table, "a.*", func(event any) { got = append(got, "removed") })
+	removeDeep := RegisterRemovable(table, "a.**.c", func(event any) { got = append(got, "deep") })
+	assert.EqualErrorf(t, 2, len(table.CBList("a.b")), "patterns")
+	assert.EqualErrorf(t, 1, len(table.CBList("a.b.c")), "deep pattern")
+	remove()
+	remove()
+	removeDeep()
+	assert.EqualErrorf(t, 1, len(table.CBList("a.b")), "pattern removed")
+	assert.EqualErrorf(t, 0, len(table.CBList("a.b.c")), "deep pattern removed")
+	assert.EqualErrorf(t, 1, len(table.root.children["a"].children), "pruned")
+	Dispatch(table, "a.b", 0)
+	assert.DeepEqualErrorf(t, []string{"kept"}, got, "calls")
+}
+
+func TestSubscribeTypeMismatch(t *testing.T) {
+	table := NewTypedEventTable(NewEventTable(), false)
+	EventName[int]("x").On(table, func(event int) {})
+	_, _, err := EventName[string]("x").Subscribe(table, 1, DropNewest)
+	var mismatch *TypeMismatchError
+	assert.EqualErrorf(t, true, errors.As(err, &mismatch), "mismatch: %v", err)
+}
@@ -1,9 +1,14 @@
 package trigger
 
-import "sync"
+import (
+	"slices"
+	"sync"
+)
 
 type EventTable struct {
 	cbList map[string][]CB
+	cbIDs  map[string][]uint64 // parallel to cbList for keys holding removable CBs, 0 for the others
+	lastID uint64
 }
 
 func NewEventTable() EventTableI {
@@ -12,6 +17,42 @@ func NewEventTable() EventTableI {
 
 func (t *EventTable) RegisterCB(key string, cb CB) {
 	t.cbList[key] = append(t.cbList[key], cb)
+	if ids, ok := t.cbIDs[key]; ok {
+		t.cbIDs[key] = append(ids, 0)
+	}
+}
+
+func (t *EventTable) RegisterRemovableCB(key string, cb CB) (remove func()) {
+	if t.cbIDs == nil {
+		t.cbIDs = make(map[string][]uint64)
+	}
+	ids := t.cbIDs[key]
+	for len(ids) < len(t.cbList[key]) {
+		ids = append(ids, 0)
+	}
+	t.lastID++
+	id := t.lastID
+	t.cbList[key] = append(t.cbList[key], cb)
+	t.cbIDs[key] = append(ids, id)
+	return func() { t.removeCB(key, id) }
+}
+
+// removeCB builds new slices rather than shifting in place, so that lists
+// returned by CBList earlier stay intact while they are being iterated.
+func (t *EventTable) removeCB(key string, id uint64) {
+	ids := t.cbIDs[key]
+	i := slices.Index(ids, id)
+	if i < 0 {
+		return
+	}
+	if len(ids) == 1 {
+		delete(t.cbList, key)
+		delete(t.cbIDs, key)
+		return
+	}
+	cbs := t.cbList[key]
+	t.cbList[key] = append(cbs[:i:i], cbs[i+1:]...)
+	t.cbIDs[key] = append(ids[:i:i], ids[i+1:]...)
 }
 
 func (t *EventTable) CBList(key string) []CB {
@@ -33,6 +74,17 @@ func (t *EventTableMutex) RegisterCB(key string, cb CB) {
 	t.EventTable.RegisterCB(key, cb)
 }
 
+func (t *EventTableMutex) RegisterRemovableCB(key string, cb CB) (remove func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	removeCB := t.EventTable.RegisterRemovableCB(key, cb)
+	return func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		removeCB()
+	}
+}
+
 func (t *EventTableMutex) CBList(key string) []CB {
 	t.mu.RLock()
 	defer t.mu.RUnlock()
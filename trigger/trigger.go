@@ -1,28 +1,100 @@
 package trigger
 
+import (
+	"fmt"
+	"reflect"
+	"sync/atomic"
+)
+
 type EventTableI interface {
 	RegisterCB(key string, cb CB)
 	CBList(key string) []CB
 }
 
+// RemovableEventTableI is implemented by tables whose registrations can be
+// undone.
+type RemovableEventTableI interface {
+	EventTableI
+	RegisterRemovableCB(key string, cb CB) (remove func())
+}
+
+// RegisterRemovable registers cb for key on t and returns a func that
+// removes it again. On tables that can't remove handlers, cb is wrapped so
+// that it does nothing once removed.
+func RegisterRemovable(t EventTableI, key string, cb CB) (remove func()) {
+	if r, ok := t.(RemovableEventTableI); ok {
+		return r.RegisterRemovableCB(key, cb)
+	}
+	var removed atomic.Bool
+	t.RegisterCB(key, func(event any) {
+		if !removed.Load() {
+			cb(event)
+		}
+	})
+	return func() { removed.Store(true) }
+}
+
+// Dispatcher is implemented by tables that deliver triggered events
+// themselves instead of having the caller run CBList in place.
+type Dispatcher interface {
+	Dispatch(key string, event any) error
+}
+
+// Dispatch delivers event to the handlers registered under key on t.
+func Dispatch(t EventTableI, key string, event any) error {
+	if d, ok := t.(Dispatcher); ok {
+		return d.Dispatch(key, event)
+	}
+	for _, cb := range t.CBList(key) {
+		cb(event)
+	}
+	return nil
+}
+
 type CB func(event any)
 
-func eraseArgType[T any](f func(arg T)) CB {
+func eraseArgType[T any](key string, f func(arg T)) CB {
 	return func(event any) {
-		f(event.(T))
+		f(argOf[T](key, event))
+	}
+}
+
+// argOf asserts that the event triggered for key is a T, a nil event being
+// the zero T.
+func argOf[T any](key string, event any) T {
+	v, ok := event.(T)
+	if !ok && event != nil {
+		panic(fmt.Sprintf("trigger: handler of %q expects %v, got %T", key, reflect.TypeFor[T](), event))
 	}
+	return v
 }
 
 type EventI interface{}
 
 type EventName[T EventI] string
 
-func (e EventName[T]) On(t EventTableI, cb func(event T)) {
-	t.RegisterCB(string(e), eraseArgType(cb))
+// On registers cb for e. On a TypeChecker table it returns a
+// *TypeMismatchError, or panics in strict mode, when e is already bound to
+// another payload type.
+func (e EventName[T]) On(t EventTableI, cb func(event T)) error {
+	if err := checkOn[T](t, string(e)); err != nil {
+		return err
+	}
+	t.RegisterCB(string(e), eraseArgType(string(e), cb))
+	return nil
 }
 
-func (e EventName[T]) Trigger(t EventTableI, event T) {
-	for _, cb := range t.CBList(string(e)) {
-		cb(event)
+// OnRemovable is On returning a func that removes cb again.
+func (e EventName[T]) OnRemovable(t EventTableI, cb func(event T)) (remove func(), err error) {
+	if err := checkOn[T](t, string(e)); err != nil {
+		return nil, err
+	}
+	return RegisterRemovable(t, string(e), eraseArgType(string(e), cb)), nil
+}
+
+func (e EventName[T]) Trigger(t EventTableI, event T) error {
+	if err := checkType[T](t, string(e)); err != nil {
+		return err
 	}
+	return Dispatch(t, string(e), event)
 }
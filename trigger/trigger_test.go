@@ -1,7 +1,6 @@
 package trigger
 
 import (
-	"fmt"
 	"github.com/hyicode/utils/assert"
 	"runtime"
 	"strconv"
@@ -31,29 +30,65 @@ func TestNewEventTable(t *testing.T) {
 }
 
 func BenchmarkNewEventTable(b *testing.B) {
+	benchmarks := []struct {
+		name     string
+		newTable func() EventTableI
+		parallel bool
+	}{
+		{name: "EventTable", newTable: NewEventTable},
+		{name: "EventTableMutex", newTable: NewEventTableMutex},
+		{name: "EventTableCOW", newTable: NewEventTableCOW},
+		{name: "EventTableMutexParallel", newTable: NewEventTableMutex, parallel: true},
+		{name: "EventTableCOWParallel", newTable: NewEventTableCOW, parallel: true},
+	}
+	for _, bm := range benchmarks {
+		// built by the first run only, so that filtered out benchmarks don't
+		// pay for their 10M handlers
+		var (
+			table   EventTableI
+			keys    []EventName[int]
+			counter int
+		)
+		b.Run(bm.name, func(b *testing.B) {
+			if table == nil {
+				table, keys = newBenchmarkTable(bm.newTable, func(event int) {
+					if !bm.parallel {
+						counter++
+					}
+				})
+				b.ResetTimer()
+			}
+			if bm.parallel {
+				b.RunParallel(func(pb *testing.PB) {
+					for i := 0; pb.Next(); i++ {
+						keys[i%len(keys)].Trigger(table, i)
+					}
+				})
+				return
+			}
+			for i := 0; i < b.N; i++ {
+				key := keys[i%len(keys)]
+				key.Trigger(table, i)
+			}
+		})
+	}
+}
+
+func newBenchmarkTable(newTable func() EventTableI, cb func(event int)) (EventTableI, []EventName[int]) {
 	const (
 		KeyNum = 100000
 		CbNum  = 100
 	)
 
-	table := NewEventTable()
+	table := newTable()
 	keys := make([]EventName[int], 0, KeyNum)
-	counter := 0
 	for i := 0; i < KeyNum; i++ {
 		key := EventName[int]("test_" + strconv.Itoa(i))
 		for j := 0; j < CbNum; j++ {
-			key.On(table, func(event int) {
-				counter++
-			})
+			key.On(table, cb)
 		}
 		keys = append(keys, key)
 	}
-
 	runtime.GC()
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		key := keys[i%KeyNum]
-		key.Trigger(table, i)
-	}
-	fmt.Println(counter)
+	return table, keys
 }
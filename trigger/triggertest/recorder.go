@@ -0,0 +1,175 @@
+// Package triggertest records the events triggered on a table so tests can
+// assert on them:
+//
+//	r := triggertest.NewRecorder(trigger.NewEventTable())
+//	game.Start(r)
+//	r.ExpectSequence(t, "round.start", "turn.start")
+//	scores := triggertest.Payloads(r, ScoreChanged)
+package triggertest
+
+import (
+	"github.com/hyicode/utils/trigger"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Event is a recorded trigger.
+type Event struct {
+	Key     string
+	Payload any
+}
+
+// Recorder is a table recording every event triggered on it before
+// dispatching it to the table it wraps. It is safe for concurrent use.
+type Recorder struct {
+	table trigger.EventTableI
+
+	mu      sync.Mutex
+	events  []Event
+	changed chan struct{} // closed when an event is recorded
+}
+
+func NewRecorder(table trigger.EventTableI) *Recorder {
+	return &Recorder{table: table, changed: make(chan struct{})}
+}
+
+func (r *Recorder) RegisterCB(key string, cb trigger.CB) {
+	r.table.RegisterCB(key, cb)
+}
+
+func (r *Recorder) RegisterRemovableCB(key string, cb trigger.CB) (remove func()) {
+	return trigger.RegisterRemovable(r.table, key, cb)
+}
+
+func (r *Recorder) CBList(key string) []trigger.CB {
+	return r.table.CBList(key)
+}
+
+func (r *Recorder) Dispatch(key string, event any) error {
+	r.mu.Lock()
+	r.events = append(r.events, Event{Key: key, Payload: event})
+	close(r.changed)
+	r.changed = make(chan struct{})
+	r.mu.Unlock()
+	return trigger.Dispatch(r.table, key, event)
+}
+
+// Events returns the events recorded so far, in trigger order.
+func (r *Recorder) Events() []Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.events)
+}
+
+// Keys returns the keys of the events recorded so far, in trigger order.
+func (r *Recorder) Keys() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	keys := make([]string, len(r.events))
+	for i, e := range r.events {
+		keys[i] = e.Key
+	}
+	return keys
+}
+
+// Reset forgets the events recorded so far.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = nil
+}
+
+// ExpectSequence reports an error unless events of keys were recorded in
+// that order, other events possibly in between.
+func (r *Recorder) ExpectSequence(tb testing.TB, keys ...string) bool {
+	tb.Helper()
+	recorded := r.Keys()
+	i := 0
+	for _, key := range recorded {
+		if i < len(keys) && key == keys[i] {
+			i++
+		}
+	}
+	if i < len(keys) {
+		tb.Errorf("triggertest: expect sequence %q, missing %q, recorded %q", keys, keys[i], recorded)
+		return false
+	}
+	return true
+}
+
+// ExpectNone reports an error if an event of keys was recorded, or any
+// event when keys is empty.
+func (r *Recorder) ExpectNone(tb testing.TB, keys ...string) bool {
+	tb.Helper()
+	recorded := r.Keys()
+	for _, key := range recorded {
+		if len(keys) == 0 || slices.Contains(keys, key) {
+			tb.Errorf("triggertest: expect no %q, recorded %q", key, recorded)
+			return false
+		}
+	}
+	return true
+}
+
+// WaitFor returns the first event of key recorded, waiting up to timeout
+// for one to be triggered.
+func (r *Recorder) WaitFor(key string, timeout time.Duration) (Event, bool) {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	for {
+		r.mu.Lock()
+		for _, e := range r.events {
+			if e.Key == key {
+				r.mu.Unlock()
+				return e, true
+			}
+		}
+		changed := r.changed
+		r.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-deadline.C:
+			return Event{}, false
+		}
+	}
+}
+
+// Payloads returns the payloads of the events of e recorded so far.
+func Payloads[T any](r *Recorder, e trigger.EventName[T]) []T {
+	var payloads []T
+	for _, ev := range r.Events() {
+		if ev.Key == string(e) {
+			if v, ok := ev.Payload.(T); ok {
+				payloads = append(payloads, v)
+			}
+		}
+	}
+	return payloads
+}
+
+// Last returns the payload of the last event of e recorded.
+func Last[T any](r *Recorder, e trigger.EventName[T]) (T, bool) {
+	payloads := Payloads(r, e)
+	if len(payloads) == 0 {
+		var zero T
+		return zero, false
+	}
+	return payloads[len(payloads)-1], true
+}
+
+// WaitPayload is WaitFor for a typed event, failing tb on timeout.
+func WaitPayload[T any](tb testing.TB, r *Recorder, e trigger.EventName[T], timeout time.Duration) T {
+	tb.Helper()
+	ev, ok := r.WaitFor(string(e), timeout)
+	if !ok {
+		tb.Fatalf("triggertest: no %q triggered within %v", string(e), timeout)
+	}
+	v, ok := ev.Payload.(T)
+	if !ok && ev.Payload != nil {
+		tb.Fatalf("triggertest: %q payload is %T, not %T", string(e), ev.Payload, v)
+	}
+	return v
+}
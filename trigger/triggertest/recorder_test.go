@@ -0,0 +1,77 @@
+package triggertest
+
+import (
+	"fmt"
+	"github.com/hyicode/utils/assert"
+	"github.com/hyicode/utils/trigger"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeTB collects the failures reported to it.
+type fakeTB struct {
+	testing.TB
+	failures []string
+}
+
+func (tb *fakeTB) Helper() {}
+
+func (tb *fakeTB) Errorf(format string, args ...any) {
+	tb.failures = append(tb.failures, fmt.Sprintf(format, args...))
+}
+
+const (
+	Start trigger.EventName[string] = "start"
+	Score trigger.EventName[int]    = "score"
+	Stop  trigger.EventName[string] = "stop"
+)
+
+func TestRecorder(t *testing.T) {
+	r := NewRecorder(trigger.NewEventTable())
+	total := 0
+	Score.On(r, func(points int) { total += points })
+	Start.On(r, func(string) { Score.Trigger(r, 1) })
+
+	Start.Trigger(r, "go")
+	Score.Trigger(r, 5)
+
+	assert.EqualErrorf(t, 6, total, "handlers still called")
+	assert.EqualErrorf(t, "start score score", strings.Join(r.Keys(), " "), "keys")
+	assert.EqualErrorf(t, "[1 5]", fmt.Sprint(Payloads(r, Score)), "payloads")
+	last, ok := Last(r, Score)
+	assert.EqualErrorf(t, true, ok && last == 5, "last score %d", last)
+	_, ok = Last(r, Stop)
+	assert.EqualErrorf(t, false, ok, "last stop")
+
+	tb := &fakeTB{TB: t}
+	assert.EqualErrorf(t, true, r.ExpectSequence(tb, "start", "score"), "sequence")
+	assert.EqualErrorf(t, true, r.ExpectSequence(tb, "start", "score", "score"), "exact sequence")
+	assert.EqualErrorf(t, true, r.ExpectNone(tb, "stop"), "none of stop")
+	assert.EqualErrorf(t, 0, len(tb.failures), "failures: %q", tb.failures)
+
+	assert.EqualErrorf(t, false, r.ExpectSequence(tb, "score", "start"), "wrong order")
+	assert.EqualErrorf(t, false, r.ExpectNone(tb, "stop", "score"), "none of score")
+	assert.EqualErrorf(t, false, r.ExpectNone(tb), "none at all")
+	assert.EqualFatalf(t, 3, len(tb.failures), "failures: %q", tb.failures)
+	assert.EqualErrorf(t, `triggertest: expect sequence ["score" "start"], missing "start", recorded ["start" "score" "score"]`,
+		tb.failures[0], "sequence failure")
+
+	r.Reset()
+	assert.EqualErrorf(t, true, r.ExpectNone(tb), "none after reset")
+}
+
+func TestRecorderWaitFor(t *testing.T) {
+	r := NewRecorder(trigger.NewEventTableMutex())
+	_, ok := r.WaitFor("stop", time.Millisecond)
+	assert.EqualErrorf(t, false, ok, "wait without trigger")
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		Score.Trigger(r, 1)
+		Stop.Trigger(r, "done")
+	}()
+	assert.EqualErrorf(t, "done", WaitPayload(t, r, Stop, 5*time.Second), "stop payload")
+	e, ok := r.WaitFor("score", 0)
+	assert.EqualErrorf(t, true, ok && e.Payload == 1, "score recorded before %v", e)
+}
@@ -0,0 +1,118 @@
+package trigger
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// TypeChecker is implemented by tables that bind every key to the payload
+// type it is first used with.
+type TypeChecker interface {
+	// CheckType binds key to typ on first use and returns a
+	// *TypeMismatchError when key is already bound to another type.
+	CheckType(key string, typ reflect.Type) error
+	// Strict reports whether registering a mismatching handler panics
+	// instead of returning the error.
+	Strict() bool
+}
+
+type TypeMismatchError struct {
+	Key    string
+	Expect reflect.Type // type the key was first used with
+	Actual reflect.Type
+}
+
+func (e *TypeMismatchError) Error() string {
+	return fmt.Sprintf("trigger: event %q is bound to payload type %v, used with %v", e.Key, e.Expect, e.Actual)
+}
+
+func checkType[T any](t EventTableI, key string) error {
+	if c, ok := t.(TypeChecker); ok {
+		return c.CheckType(key, reflect.TypeFor[T]())
+	}
+	return nil
+}
+
+// checkOn is checkType for registrations, which panic on strict tables.
+func checkOn[T any](t EventTableI, key string) error {
+	err := checkType[T](t, key)
+	if err != nil && t.(TypeChecker).Strict() {
+		panic(err)
+	}
+	return err
+}
+
+type KeyType struct {
+	Key  string
+	Type reflect.Type
+}
+
+// TypedEventTable records the payload type of every key on top of table, so
+// that EventName[int]("x") and EventName[string]("x") report an error
+// instead of panicking inside a handler. Wrap an AsyncEventTable rather than
+// the other way round, so the check runs on the triggering goroutine.
+type TypedEventTable struct {
+	table  EventTableI
+	strict bool
+
+	mu    sync.RWMutex
+	types map[string]reflect.Type
+}
+
+func NewTypedEventTable(table EventTableI, strict bool) *TypedEventTable {
+	return &TypedEventTable{table: table, strict: strict, types: make(map[string]reflect.Type)}
+}
+
+func (t *TypedEventTable) RegisterCB(key string, cb CB) {
+	t.table.RegisterCB(key, cb)
+}
+
+func (t *TypedEventTable) RegisterRemovableCB(key string, cb CB) (remove func()) {
+	return RegisterRemovable(t.table, key, cb)
+}
+
+func (t *TypedEventTable) CBList(key string) []CB {
+	return t.table.CBList(key)
+}
+
+func (t *TypedEventTable) Dispatch(key string, event any) error {
+	return Dispatch(t.table, key, event)
+}
+
+func (t *TypedEventTable) Strict() bool {
+	return t.strict
+}
+
+func (t *TypedEventTable) CheckType(key string, typ reflect.Type) error {
+	t.mu.RLock()
+	bound, ok := t.types[key]
+	t.mu.RUnlock()
+	if !ok {
+		t.mu.Lock()
+		if bound, ok = t.types[key]; !ok {
+			t.types[key] = typ
+			bound = typ
+		}
+		t.mu.Unlock()
+	}
+	if bound != typ {
+		return &TypeMismatchError{Key: key, Expect: bound, Actual: typ}
+	}
+	return nil
+}
+
+// Types returns every key used so far with its payload type, sorted by key.
+func (t *TypedEventTable) Types() []KeyType {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	types := make([]KeyType, 0, len(t.types))
+	for k, typ := range t.types {
+		types = append(types, KeyType{Key: k, Type: typ})
+	}
+	sort.Slice(types, func(i, j int) bool {
+		return types[i].Key < types[j].Key
+	})
+	return types
+}
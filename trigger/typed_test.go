@@ -0,0 +1,65 @@
+package trigger
+
+import (
+	"errors"
+	"github.com/hyicode/utils/assert"
+	"reflect"
+	"testing"
+)
+
+func TestTypedEventTable(t *testing.T) {
+	const (
+		IntKey EventName[int]    = "x"
+		StrKey EventName[string] = "x"
+		Other  EventName[bool]   = "y"
+	)
+	table := NewTypedEventTable(NewEventTable(), false)
+
+	count := 0
+	assert.EqualFatalf(t, nil, IntKey.On(table, func(event int) { count += event }), "first on")
+
+	var mismatch *TypeMismatchError
+	err := StrKey.On(table, func(event string) {})
+	assert.EqualFatalf(t, true, errors.As(err, &mismatch), "on mismatch: %v", err)
+	assert.EqualErrorf(t, "x", mismatch.Key, "key")
+	assert.EqualErrorf(t, reflect.TypeFor[int](), mismatch.Expect, "expect")
+	assert.EqualErrorf(t, reflect.TypeFor[string](), mismatch.Actual, "actual")
+
+	err = StrKey.Trigger(table, "boom")
+	assert.EqualFatalf(t, true, errors.As(err, &mismatch), "trigger mismatch: %v", err)
+	assert.EqualFatalf(t, nil, IntKey.Trigger(table, 2), "trigger")
+	assert.EqualFatalf(t, 2, count, "count")
+
+	assert.EqualFatalf(t, nil, Other.Trigger(table, true), "trigger without handler")
+	types := table.Types()
+	assert.EqualFatalf(t, 2, len(types), "types")
+	assert.EqualErrorf(t, KeyType{Key: "x", Type: reflect.TypeFor[int]()}, types[0], "types[0]")
+	assert.EqualErrorf(t, KeyType{Key: "y", Type: reflect.TypeFor[bool]()}, types[1], "types[1]")
+}
+
+func TestTypedEventTableStrict(t *testing.T) {
+	const (
+		IntKey EventName[int]    = "x"
+		StrKey EventName[string] = "x"
+	)
+	table := NewTypedEventTable(NewEventTable(), true)
+	IntKey.On(table, func(event int) {})
+
+	defer func() {
+		_, ok := recover().(*TypeMismatchError)
+		assert.EqualErrorf(t, true, ok, "strict on should panic with *TypeMismatchError")
+	}()
+	StrKey.On(table, func(event string) {})
+}
+
+func TestEraseArgTypeNil(t *testing.T) {
+	const key EventName[error] = "err"
+	table := NewEventTable()
+	called := false
+	key.On(table, func(event error) {
+		called = true
+		assert.EqualErrorf(t, nil, event, "nil payload")
+	})
+	key.Trigger(table, nil)
+	assert.EqualErrorf(t, true, called, "called")
+}